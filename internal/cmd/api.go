@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// getFormat returns the output format requested in the http request r: the
+// format query parameter, the first supported media type in the Accept
+// header, or def
+func getFormat(r *http.Request, def string) (string, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		if !isValidFormat(f) {
+			return "", fmt.Errorf("invalid format")
+		}
+		return f, nil
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		switch strings.TrimSpace(mediaType) {
+		case "application/json":
+			return "json", nil
+		case "text/plain":
+			return "text", nil
+		}
+	}
+	return def, nil
+}
+
+// getFilter returns the filter flt with the filter settings in the query
+// parameters q applied
+func getFilter(q url.Values, flt dev.Filter) (dev.Filter, error) {
+	if s := q.Get("mac"); s != "" {
+		mac, err := net.ParseMAC(s)
+		if err != nil {
+			return flt, fmt.Errorf("invalid mac address")
+		}
+		flt.MAC = mac
+	}
+	if s := q.Get("ip"); s != "" {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return flt, fmt.Errorf("invalid ip address")
+		}
+		flt.IP = ip
+	}
+	if s := q.Get("vlan"); s != "" {
+		vlan, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return flt, fmt.Errorf("invalid vlan")
+		}
+		v := uint32(vlan)
+		flt.VLAN = &v
+	}
+	if s := q.Get("property"); s != "" {
+		if !dev.IsValidProperty(s) {
+			return flt, fmt.Errorf("invalid property")
+		}
+		flt.Property = s
+	}
+	if s := q.Get("age"); s != "" {
+		age, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || age == 0 {
+			return flt, fmt.Errorf("invalid age")
+		}
+		flt.MaxAge = time.Duration(age) * time.Second
+	}
+	if s := q.Get("vendor"); s != "" {
+		flt.Vendor = s
+	}
+	if s := q.Get("iface"); s != "" {
+		flt.Interface = s
+	}
+	if s := q.Get("sort"); s != "" {
+		flt.Sort = s
+	}
+	if !dev.IsValidSort(flt.Sort) {
+		return flt, fmt.Errorf("invalid sort order")
+	}
+	return flt, nil
+}
+
+// writeAPI writes v to the api client w in output format f; text output is
+// created by calling print
+func writeAPI(w http.ResponseWriter, f string, v any, print func(io.Writer)) {
+	if f == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	print(w)
+}
+
+// getAPIDevices returns the devices with the mac address in the path of the
+// api request r that match the filter in its query parameters
+func getAPIDevices(r *http.Request) ([]*dev.DeviceInfo, error) {
+	q := r.URL.Query()
+	q.Set("mac", r.PathValue("mac"))
+	flt, err := getFilter(q, dev.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	return devices.Devices(&flt), nil
+}
+
+// handleAPIDevices sends all devices matching the filter in the query
+// parameters to api clients
+func handleAPIDevices(w http.ResponseWriter, r *http.Request) {
+	f, err := getFormat(r, "json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flt, err := getFilter(r.URL.Query(), dev.Filter{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	devices.Lock()
+	defer devices.Unlock()
+	devs := devices.Devices(&flt)
+	writeAPI(w, f, devs, func(w io.Writer) {
+		devices.PrintFilter(w, &flt)
+	})
+}
+
+// handleAPIDevice sends the device with the mac address in the path to api
+// clients; if devices are keyed by vlan, the vlan query parameter selects
+// the device, otherwise the device in the lowest vlan is sent
+func handleAPIDevice(w http.ResponseWriter, r *http.Request) {
+	f, err := getFormat(r, "json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	devices.Lock()
+	defer devices.Unlock()
+	devs, err := getAPIDevices(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(devs) == 0 {
+		http.Error(w, "device not found", http.StatusNotFound)
+		return
+	}
+	writeAPI(w, f, devs[0], devs[0].Print)
+}
+
+// handleAPIDeleteDevice removes the device with the mac address in the path
+// from the device table; if devices are keyed by vlan, the device is
+// removed from all vlans unless the vlan query parameter is set
+func handleAPIDeleteDevice(w http.ResponseWriter, r *http.Request) {
+	devices.Lock()
+	defer devices.Unlock()
+	devs, err := getAPIDevices(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(devs) == 0 {
+		http.Error(w, "device not found", http.StatusNotFound)
+		return
+	}
+	for _, device := range devs {
+		devices.Delete(device)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAPIStats sends statistics of the device table to api clients
+func handleAPIStats(w http.ResponseWriter, r *http.Request) {
+	f, err := getFormat(r, "json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	devices.Lock()
+	defer devices.Unlock()
+	stats := devices.Stats()
+	writeAPI(w, f, stats, stats.Print)
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// testAPIRequest sends an api request with method, url and accept header
+// and returns the response
+func testAPIRequest(method, url, accept string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, url, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	newHTTPHandler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIDevices(t *testing.T) {
+	devices = dev.DeviceMap{}
+	device := devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	device.Router.Enable()
+	device.UCasts.Add(layers.NewIPEndpoint(net.ParseIP("192.0.2.1")))
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})).VLANs.Add(10, "VLAN")
+
+	// get devices with filters
+	for _, test := range []struct {
+		url  string
+		want []string
+	}{
+		{"/api/v1/devices",
+			[]string{"00:00:5e:00:53:01", "00:00:5e:00:53:02"}},
+		{"/api/v1/devices?mac=00:00:5e:00:53:02",
+			[]string{"00:00:5e:00:53:02"}},
+		{"/api/v1/devices?ip=192.0.2.1",
+			[]string{"00:00:5e:00:53:01"}},
+		{"/api/v1/devices?vlan=10",
+			[]string{"00:00:5e:00:53:02"}},
+		{"/api/v1/devices?property=router",
+			[]string{"00:00:5e:00:53:01"}},
+		{"/api/v1/devices?vendor=none", []string{}},
+	} {
+		rec := testAPIRequest("GET", test.url, "")
+		var devs []struct {
+			MAC string `json:"mac"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &devs); err != nil {
+			t.Fatalf("%s: %v", test.url, err)
+		}
+		got := []string{}
+		for _, d := range devs {
+			got = append(got, d.MAC)
+		}
+		if len(got) != len(test.want) ||
+			(len(got) > 0 && got[0] != test.want[0]) {
+			t.Errorf("%s: got = %v; want %v", test.url, got,
+				test.want)
+		}
+	}
+
+	// invalid filters
+	for _, url := range []string{
+		"/api/v1/devices?mac=invalid",
+		"/api/v1/devices?ip=invalid",
+		"/api/v1/devices?vlan=invalid",
+		"/api/v1/devices?property=invalid",
+		"/api/v1/devices?age=invalid",
+		"/api/v1/devices?format=invalid",
+	} {
+		rec := testAPIRequest("GET", url, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got = %d; want %d", url, rec.Code,
+				http.StatusBadRequest)
+		}
+	}
+
+	// text output with accept header
+	rec := testAPIRequest("GET", "/api/v1/devices?mac=00:00:5e:00:53:02",
+		"text/plain")
+	want := "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 00:00:5e:00:53:02                           " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    VLAN: 10                                     " +
+		"(age: -1, pkts: 0)\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestAPIDevice(t *testing.T) {
+	devices = dev.DeviceMap{}
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+
+	// get device as json and text
+	rec := testAPIRequest("GET", "/api/v1/devices/00:00:5e:00:53:01", "")
+	var device struct {
+		MAC string `json:"mac"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &device); err != nil {
+		t.Fatal(err)
+	}
+	if device.MAC != "00:00:5e:00:53:01" {
+		t.Errorf("got = %s; want 00:00:5e:00:53:01", device.MAC)
+	}
+	rec = testAPIRequest("GET", "/api/v1/devices/00:00:5e:00:53:01",
+		"text/plain, application/json")
+	want := "MAC: 00:00:5e:00:53:01                           " +
+		"(age: -1, pkts: 0)\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// get unknown and invalid devices
+	rec = testAPIRequest("GET", "/api/v1/devices/00:00:5e:00:53:02", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusNotFound)
+	}
+	rec = testAPIRequest("GET", "/api/v1/devices/invalid", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	// delete device
+	rec = testAPIRequest("DELETE", "/api/v1/devices/00:00:5e:00:53:01", "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusNoContent)
+	}
+	rec = testAPIRequest("DELETE", "/api/v1/devices/00:00:5e:00:53:01", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAPIStats(t *testing.T) {
+	devices = dev.DeviceMap{}
+	devices.Packets = 2
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})).Router.Enable()
+
+	rec := testAPIRequest("GET", "/api/v1/stats", "application/json")
+	var stats dev.Stats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Packets != 2 || stats.Devices != 1 ||
+		stats.Properties[dev.PropertyRouter] != 1 {
+		t.Errorf("got = %+v; want 2 packets, 1 device, 1 router",
+			stats)
+	}
+	want := "application/json"
+	if got := rec.Header().Get("Content-Type"); got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
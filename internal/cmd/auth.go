@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// httpRole is the role of an http client
+type httpRole int
+
+// http client roles
+const (
+	// roleNone is the role of unauthenticated clients
+	roleNone httpRole = iota
+
+	// roleRead is the role of clients that can only view the device table
+	roleRead
+
+	// roleAdmin is the role of clients that can also flush the device
+	// table and delete devices
+	roleAdmin
+)
+
+// parseRole parses the role in s
+func parseRole(s string) (httpRole, error) {
+	switch s {
+	case "read":
+		return roleRead, nil
+	case "admin":
+		return roleAdmin, nil
+	}
+	return roleNone, fmt.Errorf("invalid role %q", s)
+}
+
+// httpUser is a user for http basic authentication
+type httpUser struct {
+	password [sha256.Size]byte
+	role     httpRole
+}
+
+// httpAuth stores the credentials of http clients; passwords and tokens are
+// stored as sha256 hashes
+type httpAuth struct {
+	sync.RWMutex
+	users  map[string]httpUser
+	tokens map[[sha256.Size]byte]httpRole
+}
+
+// loadHTTPAuth loads the credentials of http clients from file; each line
+// contains a role, "read" or "admin", and either "basic" with user name and
+// password or "token" with a bearer token, e.g., "admin basic alice secret"
+// or "read token 0123456789abcdef"; empty lines and lines starting with "#"
+// are ignored
+func loadHTTPAuth(file string) (*httpAuth, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a := &httpAuth{
+		users:  make(map[string]httpUser),
+		tokens: make(map[[sha256.Size]byte]httpRole),
+	}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%s:%d: invalid credentials", file,
+				n)
+		}
+		role, err := parseRole(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, n, err)
+		}
+		switch {
+		case fields[1] == "basic" && len(fields) == 4:
+			a.users[fields[2]] = httpUser{
+				password: sha256.Sum256([]byte(fields[3])),
+				role:     role,
+			}
+		case fields[1] == "token" && len(fields) == 3:
+			a.tokens[sha256.Sum256([]byte(fields[2]))] = role
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid credentials", file,
+				n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload reloads the credentials from file; if loading fails, it keeps the
+// current credentials
+func (a *httpAuth) reload(file string) error {
+	n, err := loadHTTPAuth(file)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	a.users = n.users
+	a.tokens = n.tokens
+	a.Unlock()
+	return nil
+}
+
+// authenticate returns the role of the client that sent the http request r
+func (a *httpAuth) authenticate(r *http.Request) httpRole {
+	a.RLock()
+	defer a.RUnlock()
+
+	// bearer token
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"),
+		"Bearer "); ok {
+		return a.tokens[sha256.Sum256([]byte(token))]
+	}
+
+	// basic authentication
+	name, password, ok := r.BasicAuth()
+	if !ok {
+		return roleNone
+	}
+	user, ok := a.users[name]
+	hash := sha256.Sum256([]byte(password))
+	if subtle.ConstantTimeCompare(hash[:], user.password[:]) != 1 || !ok {
+		return roleNone
+	}
+	return user.role
+}
+
+// roleKey is the context key of the client's role in http requests
+type roleKey struct{}
+
+// handler returns an http handler that authenticates clients and passes
+// authenticated requests with the client's role to next
+func (a *httpAuth) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := a.authenticate(r)
+		if role == roleNone {
+			w.Header().Set("WWW-Authenticate",
+				`Basic realm="listnd", charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), roleKey{}, role)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isAdmin returns whether the client that sent the http request r is an
+// admin; without authentication, all clients are admins
+func isAdmin(r *http.Request) bool {
+	role, ok := r.Context().Value(roleKey{}).(httpRole)
+	return !ok || role == roleAdmin
+}
+
+// requireAdmin returns an http handler that only passes requests of admins
+// to h
+func requireAdmin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdmin(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h(w, r)
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// testAuthFile writes the credentials to a temporary file and returns its
+// path
+func testAuthFile(t *testing.T, credentials string) string {
+	file := filepath.Join(t.TempDir(), "auth")
+	if err := os.WriteFile(file, []byte(credentials), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadHTTPAuth(t *testing.T) {
+	// valid credentials
+	a, err := loadHTTPAuth(testAuthFile(t, "# comment\n\n"+
+		"admin basic alice secret\n"+
+		"read basic bob password\n"+
+		"read token 0123456789abcdef\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.users) != 2 || len(a.tokens) != 1 ||
+		a.users["alice"].role != roleAdmin ||
+		a.users["bob"].role != roleRead {
+		t.Errorf("got = %+v; want 2 users and 1 token", a)
+	}
+
+	// invalid credentials
+	for _, credentials := range []string{
+		"root basic alice secret\n",
+		"admin basic alice\n",
+		"admin token\n",
+		"admin token a b\n",
+		"admin digest alice secret\n",
+	} {
+		_, err := loadHTTPAuth(testAuthFile(t, credentials))
+		if err == nil {
+			t.Errorf("%q: got nil; want error", credentials)
+		}
+	}
+
+	// missing file
+	_, err = loadHTTPAuth(filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Errorf("got nil; want error")
+	}
+}
+
+func TestHTTPAuth(t *testing.T) {
+	var err error
+	httpAuthConfig, err = loadHTTPAuth(testAuthFile(t,
+		"admin basic alice secret\n"+
+			"read basic bob password\n"+
+			"read token readtoken\n"+
+			"admin token admintoken\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		httpAuthConfig = nil
+	}()
+	handler := newHTTPHandler()
+
+	// request sends a request with method and url and the credentials
+	// user and password or token to the handler and returns the
+	// status code
+	request := func(method, url, user, password, token string) int {
+		devices = dev.DeviceMap{}
+		devices.Add(layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, nil)
+		if user != "" {
+			req.SetBasicAuth(user, password)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	const device = "/api/v1/devices/00:00:5e:00:53:01"
+	for _, test := range []struct {
+		method, url, user, password, token string
+		want                               int
+	}{
+		// unauthenticated
+		{"GET", "/", "", "", "", http.StatusUnauthorized},
+		{"GET", "/metrics", "", "", "", http.StatusUnauthorized},
+		{"GET", "/", "alice", "wrong", "", http.StatusUnauthorized},
+		{"GET", "/", "eve", "secret", "", http.StatusUnauthorized},
+		{"GET", "/", "", "", "wrong", http.StatusUnauthorized},
+
+		// read-only
+		{"GET", "/", "bob", "password", "", http.StatusOK},
+		{"GET", device, "bob", "password", "", http.StatusOK},
+		{"GET", "/?flush=true", "bob", "password", "",
+			http.StatusForbidden},
+		{"DELETE", device, "bob", "password", "",
+			http.StatusForbidden},
+		{"GET", "/api/v1/stats", "", "", "readtoken", http.StatusOK},
+		{"DELETE", device, "", "", "readtoken", http.StatusForbidden},
+
+		// admin
+		{"GET", "/?flush=true", "alice", "secret", "", http.StatusOK},
+		{"DELETE", device, "alice", "secret", "", http.StatusNoContent},
+		{"DELETE", device, "", "", "admintoken",
+			http.StatusNoContent},
+	} {
+		got := request(test.method, test.url, test.user,
+			test.password, test.token)
+		if got != test.want {
+			t.Errorf("%s %s (%s, %s): got = %d; want %d",
+				test.method, test.url, test.user, test.token,
+				got, test.want)
+		}
+	}
+
+	// flush by read-only clients keeps the device table
+	request("GET", "/?flush=true", "bob", "password", "")
+	if devices.Stats().Devices != 1 {
+		t.Errorf("read-only client flushed the device table")
+	}
+}
+
+func TestHTTPAuthDisabled(t *testing.T) {
+	devices = dev.DeviceMap{}
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	rec := testAPIRequest("DELETE", "/api/v1/devices/00:00:5e:00:53:01",
+		"")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusNoContent)
+	}
+}
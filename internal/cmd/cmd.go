@@ -3,6 +3,8 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/hwipl/listnd/internal/dev"
 	"github.com/hwipl/listnd/internal/pkt"
@@ -10,32 +12,69 @@ import (
 
 var (
 	// pcap settings
-	pcapDevice  string
-	pcapFile    string
+	pcapDevices listFlag
+	pcapFiles   listFlag
 	pcapPromisc bool = true
 	pcapSnaplen int  = 1024
 	pcapTimeout int  = 1
 	pcapFilter  string
 
 	// parsing/output settings
-	interval  int  = 5
-	debugMode bool = false
-	withPeers bool = false
+	interval  int    = 5
+	debugMode bool   = false
+	withPeers bool   = false
+	format    string = "text"
+	expire    int    = 0
+	logAlerts bool   = false
+	correlate bool   = false
+	perVLAN   bool   = false
+	ouiFile   string
+	filter    dev.Filter
+
+	// rogue device detection
+	dhcpServers    string
+	routers        string
+	routerPrefixes string
 
 	// http
-	httpListen string = ""
+	httpListen   string = ""
+	httpCert     string
+	httpKey      string
+	httpAuthFile string
+
+	// state persistence
+	stateFile     string
+	stateInterval int = 60
 
 	// device table
 	devices dev.DeviceMap
+
+	// clock based on packet timestamps, used when only reading from files
+	packetClock *dev.SourcesClock
 )
 
+// listFlag is a command line flag that can be set multiple times
+type listFlag []string
+
+// String returns the values of the flag as comma-separated list
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set adds the value s to the flag
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
 // parseCommandLine parses the command line arguments
 func parseCommandLine() {
 	// define command line arguments
-	flag.StringVar(&pcapDevice, "i", pcapDevice,
-		"set the `interface` to listen on")
-	flag.StringVar(&pcapFile, "f", pcapFile,
-		"set the pcap `file` to read packets from")
+	flag.Var(&pcapDevices, "i",
+		"set the `interface` to listen on (can be used multiple times)")
+	flag.Var(&pcapFiles, "f",
+		"set the pcap `file` to read packets from "+
+			"(can be used multiple times)")
 	flag.BoolVar(&pcapPromisc, "pcap-promisc", pcapPromisc,
 		"set pcap promiscuous parameter")
 	flag.IntVar(&pcapTimeout, "pcap-timeout", pcapTimeout,
@@ -47,29 +86,139 @@ func parseCommandLine() {
 	flag.BoolVar(&debugMode, "debug", debugMode, "set debugging mode")
 	flag.BoolVar(&withPeers, "peers", withPeers, "show peers")
 	flag.StringVar(&httpListen, "http", httpListen,
-		"use http server and set the listen `address` (e.g.: :8000)")
+		"use http server and set the comma-separated listen "+
+			"`addresses` (e.g.: :8000 or 127.0.0.1:8000,[::1]:8000)")
+	flag.StringVar(&httpCert, "http-cert", httpCert,
+		"use tls in http server and set the certificate `file`")
+	flag.StringVar(&httpKey, "http-key", httpKey,
+		"use tls in http server and set the private key `file`")
+	flag.StringVar(&httpAuthFile, "http-auth", httpAuthFile,
+		"require authentication in http server and read the "+
+			"credentials from `file`")
 	flag.IntVar(&interval, "interval", interval,
 		"set output interval to `seconds`")
+	flag.StringVar(&format, "format", format,
+		"set output `format` (text or json)")
+	flag.IntVar(&expire, "expire", expire,
+		"remove entries not seen for `seconds` (0 disables expiry)")
+	flag.StringVar(&ouiFile, "oui", ouiFile,
+		"load vendors from IEEE registry `file` (MA-L, MA-M or MA-S "+
+			"in csv or txt format) instead of the embedded snapshot")
+	flag.StringVar(&filter.Vendor, "vendor", filter.Vendor,
+		"only show devices with vendor containing `text`")
+	flag.StringVar(&filter.Interface, "iface", filter.Interface,
+		"only show devices seen on interface or pcap file `name`")
+	flag.StringVar(&filter.Sort, "sort", filter.Sort,
+		"sort devices by `field` (mac or vendor)")
+	flag.BoolVar(&correlate, "correlate", correlate,
+		"correlate devices with randomized MAC addresses to other "+
+			"devices using DHCP client ids and hostnames")
+	flag.StringVar(&stateFile, "state", stateFile,
+		"load the device table from and save it to state `file`")
+	flag.IntVar(&stateInterval, "state-interval", stateInterval,
+		"save the device table to the state file every `seconds` "+
+			"(0 only saves on exit)")
+	flag.BoolVar(&perVLAN, "per-vlan", perVLAN,
+		"key devices by VLAN and MAC address and group output by VLAN")
+	flag.BoolVar(&logAlerts, "log-alerts", logAlerts,
+		"log new alerts to the console")
+	flag.StringVar(&dhcpServers, "dhcp-servers", dhcpServers,
+		"set comma-separated `list` of authorized dhcp servers "+
+			"(MAC address, IP address or prefix, optional @vlan)")
+	flag.StringVar(&routers, "routers", routers,
+		"set comma-separated `list` of authorized ipv6 routers "+
+			"(MAC address, IP address or prefix, optional @vlan)")
+	flag.StringVar(&routerPrefixes, "router-prefixes", routerPrefixes,
+		"set comma-separated `list` of authorized prefixes in ipv6 "+
+			"router advertisements (optional @vlan)")
 
 	// parse and overwrite default values of settings
 	flag.Parse()
+	if !isValidFormat(format) {
+		log.Fatalf("invalid output format: %s", format)
+	}
+	if !dev.IsValidSort(filter.Sort) {
+		log.Fatalf("invalid sort order: %s", filter.Sort)
+	}
+	if stateInterval < 0 {
+		log.Fatalf("invalid state interval: %d", stateInterval)
+	}
+	if (httpCert == "") != (httpKey == "") {
+		log.Fatal("http certificate and key must be set together")
+	}
 
 	// output settings
-	debug(fmt.Sprintf("Pcap Listen Device: %s", pcapDevice))
+	debug(fmt.Sprintf("Pcap Listen Devices: %s", &pcapDevices))
+	debug(fmt.Sprintf("Pcap Files: %s", &pcapFiles))
 	debug(fmt.Sprintf("Pcap Promiscuous: %t", pcapPromisc))
 	debug(fmt.Sprintf("Pcap Timeout: %d", pcapTimeout))
 	debug(fmt.Sprintf("Pcap Snaplen: %d", pcapSnaplen))
 	debug(fmt.Sprintf("Debugging Output: %t", debugMode))
 	debug(fmt.Sprintf("Peers Output: %t", withPeers))
+	debug(fmt.Sprintf("HTTP Listen: %s", httpListen))
+	debug(fmt.Sprintf("HTTP Certificate: %s", httpCert))
+	debug(fmt.Sprintf("HTTP Key: %s", httpKey))
+	debug(fmt.Sprintf("HTTP Auth File: %s", httpAuthFile))
+	debug(fmt.Sprintf("Output Format: %s", format))
+	debug(fmt.Sprintf("Expire Timeout: %d", expire))
+	debug(fmt.Sprintf("OUI File: %s", ouiFile))
+	debug(fmt.Sprintf("Vendor Filter: %s", filter.Vendor))
+	debug(fmt.Sprintf("Interface Filter: %s", filter.Interface))
+	debug(fmt.Sprintf("Sort Order: %s", filter.Sort))
+	debug(fmt.Sprintf("Correlate MACs: %t", correlate))
+	debug(fmt.Sprintf("Per-VLAN Devices: %t", perVLAN))
+	debug(fmt.Sprintf("State File: %s", stateFile))
+	debug(fmt.Sprintf("State Interval: %d", stateInterval))
+	debug(fmt.Sprintf("Log Alerts: %t", logAlerts))
+	debug(fmt.Sprintf("DHCP Servers: %s", dhcpServers))
+	debug(fmt.Sprintf("Routers: %s", routers))
+	debug(fmt.Sprintf("Router Prefixes: %s", routerPrefixes))
 }
 
 // Run is the main entry point of listnd
 func Run() {
 	parseCommandLine()
 	dev.SetDebug(debugMode)
+	ouiDB, err := dev.LoadOUIDB(ouiFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dev.SetOUIDB(ouiDB)
+	dev.SetCorrelate(correlate)
+	dev.SetVLANMode(perVLAN)
+	if usePacketClock() {
+		// use timeline of packets in pcap files as clock
+		packetClock = &dev.SourcesClock{}
+		dev.SetClock(packetClock)
+	}
 	pkt.SetDebug(debugMode)
 	pkt.SetDevices(&devices)
 	pkt.SetPeers(withPeers)
+	if err := pkt.SetDHCPServers(splitList(dhcpServers)); err != nil {
+		log.Fatal(err)
+	}
+	if err := pkt.SetRouters(splitList(routers)); err != nil {
+		log.Fatal(err)
+	}
+	if err := pkt.SetRouterPrefixes(splitList(routerPrefixes)); err != nil {
+		log.Fatal(err)
+	}
+	if logAlerts {
+		devices.Alerts.AddSink(alertLogger{})
+	}
+	if stateFile != "" {
+		// restore device table and save it periodically
+		loadState()
+		if stateInterval > 0 {
+			saveStatePeriodically()
+		}
+	}
+	if httpAuthFile != "" {
+		httpAuthConfig, err = loadHTTPAuth(httpAuthFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	if httpListen != "" {
 		// start http server and print device table to clients
 		startHTTP()
@@ -77,6 +226,11 @@ func Run() {
 		// print device table periodically to console
 		printConsole()
 	}
+	stopSignals := handleSignals()
+	defer stopSignals()
 	listen()
+	stopHTTP()
+	expireDevices()
+	saveState()
 	printTable()
 }
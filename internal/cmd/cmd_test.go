@@ -1,18 +1,53 @@
 package cmd
 
 import (
+	"net"
 	"os"
 	"testing"
 
+	"github.com/gopacket/gopacket/layers"
 	"github.com/hwipl/listnd/internal/dev"
 )
 
 func TestRun(t *testing.T) {
 	// create temporary pcap file
-	pcapFile = testListenPcapCreateDumpFile()
+	pcapFile := testListenPcapCreateDumpFile()
 	defer os.Remove(pcapFile)
+	pcapFiles = listFlag{pcapFile}
+	defer func() {
+		pcapFiles = nil
+	}()
 
 	// make sure nothing panics
 	devices = dev.DeviceMap{}
 	Run()
+
+	// reading from a file should use the packet clock, reset it
+	if packetClock == nil {
+		t.Errorf("packetClock = nil; want not nil")
+	}
+	packetClock = nil
+	dev.SetClock(nil)
+
+	// vendor lookups should use the embedded snapshot, reset it
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	if v := devices.Get(mac).Vendor; v != "ICANN, IANA Department" {
+		t.Errorf("vendor = %s; want ICANN, IANA Department", v)
+	}
+	dev.SetOUIDB(nil)
+}
+
+func TestListFlag(t *testing.T) {
+	var l listFlag
+	if err := l.Set("eth0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Set("eth1"); err != nil {
+		t.Fatal(err)
+	}
+	want := "eth0,eth1"
+	got := l.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webFiles contains the files of the web dashboard
+//
+//go:embed web
+var webFiles embed.FS
+
+// newDashboardHandler returns the http handler that serves the web dashboard
+func newDashboardHandler() http.Handler {
+	web, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix("/dashboard/", http.FileServerFS(web))
+}
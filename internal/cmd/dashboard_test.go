@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	// dashboard page
+	rec := testAPIRequest("GET", "/dashboard/", "")
+	if rec.Code != 200 {
+		t.Fatalf("code = %d; want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got,
+		"text/html") {
+		t.Errorf("content type = %s; want text/html", got)
+	}
+	if !strings.Contains(rec.Body.String(), "api/v1/devices") {
+		t.Errorf("dashboard does not use the device api")
+	}
+
+	// redirect without trailing slash
+	rec = testAPIRequest("GET", "/dashboard", "")
+	if got := rec.Header().Get("Location"); got != "/dashboard/" {
+		t.Errorf("location = %s; want /dashboard/", got)
+	}
+
+	// unknown file
+	rec = testAPIRequest("GET", "/dashboard/missing.js", "")
+	if rec.Code != 404 {
+		t.Errorf("code = %d; want 404", rec.Code)
+	}
+}
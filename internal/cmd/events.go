@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+const (
+	// eventBufferSize is the number of events buffered for an event
+	// stream client; if the buffer is full, new events are dropped
+	eventBufferSize = 256
+)
+
+// eventStream is an event sink that buffers events for an event stream
+// client
+type eventStream struct {
+	events  chan *dev.Event
+	dropped int
+}
+
+// HandleEvent buffers the event or drops it if the buffer is full
+func (e *eventStream) HandleEvent(event *dev.Event) {
+	select {
+	case e.events <- event:
+	default:
+		e.dropped++
+	}
+}
+
+// handleAPIEvents sends events to api clients as server-sent events
+func handleAPIEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported",
+			http.StatusInternalServerError)
+		return
+	}
+
+	// register event stream
+	stream := &eventStream{
+		events: make(chan *dev.Event, eventBufferSize),
+	}
+	devices.Lock()
+	devices.Events.AddSink(stream)
+	devices.Unlock()
+	defer func() {
+		devices.Lock()
+		devices.Events.RemoveSink(stream)
+		if stream.dropped > 0 {
+			debug(fmt.Sprintf("Dropped %d events", stream.dropped))
+		}
+		devices.Unlock()
+	}()
+
+	// send events until client disconnects or server shuts down
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-stream.events:
+			b, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n",
+				event.Type, b)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+func TestAPIEvents(t *testing.T) {
+	devices = dev.DeviceMap{}
+
+	// start server on random port and connect to event stream
+	httpListen = ":0"
+	startHTTP()
+	port := httpListeners[0].Addr().(*net.TCPAddr).Port
+	url := fmt.Sprintf("http://localhost:%d/api/v1/events", port)
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("got = %s; want text/event-stream", got)
+	}
+
+	// add device, the stream is registered after the response header
+	devices.Lock()
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	devices.Unlock()
+
+	// check event
+	r := bufio.NewReader(resp.Body)
+	want := []string{
+		"event: device-added\n",
+		"data: {\"time\":",
+	}
+	for _, w := range want {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(line) < len(w) || line[:len(w)] != w {
+			t.Errorf("got = %s; want %s", line, w)
+		}
+	}
+
+	// shut down server, stream should end
+	start := time.Now()
+	stopHTTP()
+	if time.Since(start) >= httpShutdownTimeout {
+		t.Errorf("shutdown took %s", time.Since(start))
+	}
+}
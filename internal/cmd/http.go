@@ -1,38 +1,135 @@
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
 	"log"
 	"net"
 	"net/http"
+	"time"
+)
+
+const (
+	// httpShutdownTimeout is the time active http requests get to
+	// complete when the http server shuts down
+	httpShutdownTimeout = 5 * time.Second
 )
 
 var (
-	httpListener net.Listener
+	httpListeners []net.Listener
+	httpServer    *http.Server
+
+	// httpAuthConfig stores the credentials of http clients; if nil,
+	// authentication is disabled
+	httpAuthConfig *httpAuth
 )
 
 // handleHTTP prints the device table to http clients
 func handleHTTP(w http.ResponseWriter, r *http.Request) {
 	flush := r.URL.Query().Get("flush")
+	if flush == "true" && !isAdmin(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	// get output format, use command line setting by default
+	f, err := getFormat(r, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get filter, use command line settings by default
+	flt, err := getFilter(r.URL.Query(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if f == "json" {
+		w.Header().Set("Content-Type", "application/json")
+	}
 
 	devices.Lock()
-	devices.Print(w)
+	printDevices(w, f, &flt)
 	if flush == "true" {
 		devices.Reset()
 	}
 	devices.Unlock()
 }
 
-// startHTTP starts the http server
+// newHTTPHandler returns the http handler with the text view, the api, the
+// web dashboard and the metrics
+func newHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleHTTP)
+	mux.HandleFunc("GET /api/v1/devices", handleAPIDevices)
+	mux.HandleFunc("GET /api/v1/devices/{mac}", handleAPIDevice)
+	mux.HandleFunc("DELETE /api/v1/devices/{mac}",
+		requireAdmin(handleAPIDeleteDevice))
+	mux.HandleFunc("GET /api/v1/stats", handleAPIStats)
+	mux.HandleFunc("GET /api/v1/events", handleAPIEvents)
+	mux.Handle("GET /dashboard/", newDashboardHandler())
+	mux.HandleFunc("GET /metrics", handleMetrics)
+	if httpAuthConfig != nil {
+		return httpAuthConfig.handler(mux)
+	}
+	return mux
+}
+
+// startHTTP starts the http server on all listen addresses
 func startHTTP() {
-	var err error
+	// create listeners
+	httpListeners = nil
+	for _, addr := range splitList(httpListen) {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		httpListeners = append(httpListeners, listener)
+	}
+
+	// start listening; cancel the context of all requests on shutdown,
+	// so event streams end
+	ctx, cancel := context.WithCancel(context.Background())
+	httpServer = &http.Server{
+		Handler: newHTTPHandler(),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	httpServer.RegisterOnShutdown(cancel)
+	if httpCert == "" {
+		for _, listener := range httpListeners {
+			go httpServer.Serve(listener)
+		}
+		return
+	}
 
-	// create listener
-	httpListener, err = net.Listen("tcp", httpListen)
+	// use tls if certificate and key are set
+	cert, err := tls.LoadX509KeyPair(httpCert, httpKey)
 	if err != nil {
 		log.Fatal(err)
 	}
+	httpServer.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	for _, listener := range httpListeners {
+		go httpServer.ServeTLS(listener, "", "")
+	}
+}
 
-	// start listening
-	http.HandleFunc("/", handleHTTP)
-	go http.Serve(httpListener, nil)
+// stopHTTP shuts down the http server gracefully
+func stopHTTP() {
+	if httpServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(),
+		httpShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Printf("error shutting down http server: %v", err)
+	}
+	httpServer = nil
 }
@@ -1,12 +1,24 @@
 package cmd
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gopacket/gopacket/layers"
 	"github.com/hwipl/listnd/internal/dev"
@@ -32,7 +44,7 @@ func TestHTTP(t *testing.T) {
 	// start server on random port
 	httpListen = ":0"
 	startHTTP()
-	port := httpListener.Addr().(*net.TCPAddr).Port
+	port := httpListeners[0].Addr().(*net.TCPAddr).Port
 
 	// get url with empty device table
 	url = fmt.Sprintf("http://localhost:%d/", port)
@@ -111,4 +123,154 @@ func TestHTTP(t *testing.T) {
 	if got != want {
 		t.Errorf("got = %s; want %s", got, want)
 	}
+
+	// stop server, requests should fail
+	stopHTTP()
+	if httpServer != nil {
+		t.Errorf("httpServer = %v; want nil", httpServer)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
+
+func TestHTTPFormat(t *testing.T) {
+	var want, got string
+	devices = dev.DeviceMap{}
+
+	// get json device table
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/?format=json", nil)
+	handleHTTP(rec, req)
+	want = "{\n" +
+		"  \"packets\": 0,\n" +
+		"  \"devices\": [],\n" +
+		"  \"alerts\": []\n" +
+		"}\n"
+	got = rec.Body.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	want = "application/json"
+	got = rec.Header().Get("Content-Type")
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// get device table with invalid format
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/?format=invalid", nil)
+	handleHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHTTPFilter(t *testing.T) {
+	var want, got string
+	devices = dev.DeviceMap{}
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})).Vendor = "Vendor"
+	devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}))
+
+	// get device table filtered by vendor
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/?vendor=vendor&sort=vendor", nil)
+	handleHTTP(rec, req)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 00:00:5e:00:53:01 (Vendor)                  " +
+		"(age: -1, pkts: 0)\n\n"
+	got = rec.Body.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test invalid sort order
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/?sort=invalid", nil)
+	handleHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got = %d; want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// testHTTPCreateCert creates a self-signed certificate for localhost in dir
+// and returns the certificate and key files
+func testHTTPCreateCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{
+		Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestHTTPTLS(t *testing.T) {
+	devices = dev.DeviceMap{}
+
+	// start tls server on two random ports
+	httpCert, httpKey = testHTTPCreateCert(t, t.TempDir())
+	defer func() {
+		httpCert, httpKey = "", ""
+	}()
+	httpListen = "127.0.0.1:0, 127.0.0.1:0"
+	startHTTP()
+	defer stopHTTP()
+	if len(httpListeners) != 2 {
+		t.Fatalf("got %d listeners; want 2", len(httpListeners))
+	}
+
+	// get device table on both ports
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	for _, listener := range httpListeners {
+		url := fmt.Sprintf("https://%s/api/v1/stats",
+			listener.Addr())
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.TLS == nil {
+			t.Errorf("%s: got = %d, %v; want 200 with tls", url,
+				resp.StatusCode, resp.TLS)
+		}
+	}
 }
@@ -1,34 +1,212 @@
 package cmd
 
 import (
+	"sync"
+	"time"
+
 	"github.com/gopacket/gopacket"
+	gopcap "github.com/gopacket/gopacket/pcap"
 
 	"github.com/hwipl/listnd/internal/pkt"
 	"github.com/hwipl/packet-go/pkg/pcap"
 )
 
-type handler struct{}
+var (
+	// listeners are the active pcap listeners; stopping closes their pcap
+	// handles, which ends the listen loops
+	listenerMu sync.Mutex
+	listeners  []*pcap.Listener
+	stopped    bool
+
+	// lastExpire is the time of the packet clock when the device table
+	// was last checked for expired entries; protected by the device table
+	// lock
+	lastExpire time.Time
+
+	// pcapStats are the pcap statistics of all network interfaces
+	pcapStatsMu sync.Mutex
+	pcapStats   = make(map[string]gopcap.Stats)
+)
+
+// pcapSource is a network interface or a pcap file to read packets from
+type pcapSource struct {
+	device string
+	file   string
+}
+
+// getPcapSources returns all network interfaces and pcap files set on the
+// command line; if none are set, it returns the default interface
+func getPcapSources() []pcapSource {
+	var sources []pcapSource
+	for _, device := range pcapDevices {
+		sources = append(sources, pcapSource{device: device})
+	}
+	for _, file := range pcapFiles {
+		sources = append(sources, pcapSource{file: file})
+	}
+	if len(sources) == 0 {
+		sources = append(sources, pcapSource{})
+	}
+	return sources
+}
+
+// usePacketClock checks if the timestamps of packets should be used as
+// clock, i.e., if all packet sources are pcap files
+func usePacketClock() bool {
+	return len(pcapFiles) > 0 && len(pcapDevices) == 0
+}
+
+// handler handles packets and timer events of a pcap listener; source is
+// the name of the listener's interface or pcap file if there are multiple
+// listeners, file is the name of the listener's pcap file
+type handler struct {
+	source   string
+	file     string
+	listener *pcap.Listener
+}
 
 func (h *handler) HandlePacket(packet gopacket.Packet) {
-	pkt.Parse(packet)
+	if packetClock != nil {
+		packetClock.Update(h.file, packet.Metadata().Timestamp)
+	}
+	pkt.ParseSource(packet, h.source)
+	if packetClock != nil {
+		expirePacketTime()
+	}
 }
 
-// listen captures packets on the network interface and parses them
+// HandleTimer removes expired entries from the device table and updates
+// the pcap statistics; with the packet clock, entries are expired on the
+// timeline of the packets instead
+func (h *handler) HandleTimer() {
+	if packetClock == nil {
+		expireDevices()
+	}
+	h.updatePcapStats()
+}
+
+// expireDevices removes expired entries from the device table if expiry is
+// enabled
+func expireDevices() {
+	if expire <= 0 {
+		return
+	}
+	devices.Lock()
+	devices.Expire(time.Duration(expire) * time.Second)
+	devices.Unlock()
+}
+
+// expirePacketTime removes expired entries from the device table if expiry
+// is enabled and the packet clock advanced by at least a second since the
+// last check
+func expirePacketTime() {
+	if expire <= 0 {
+		return
+	}
+	devices.Lock()
+	defer devices.Unlock()
+	now := packetClock.Now()
+	if now.Sub(lastExpire) < time.Second {
+		return
+	}
+	lastExpire = now
+	devices.Expire(time.Duration(expire) * time.Second)
+}
+
+// updatePcapStats updates the pcap statistics of the listener's network
+// interface; it must run in the listen loop, because the pcap handle is
+// closed when the loop ends
+func (h *handler) updatePcapStats() {
+	if h.listener == nil || h.listener.PcapHandle == nil ||
+		h.listener.File != "" {
+		return
+	}
+	stats, err := h.listener.PcapHandle.Stats()
+	if err != nil {
+		return
+	}
+	pcapStatsMu.Lock()
+	pcapStats[h.listener.Device] = *stats
+	pcapStatsMu.Unlock()
+}
+
+// getPcapStats returns the pcap statistics of all network interfaces
+func getPcapStats() map[string]gopcap.Stats {
+	pcapStatsMu.Lock()
+	defer pcapStatsMu.Unlock()
+	stats := make(map[string]gopcap.Stats, len(pcapStats))
+	for device, s := range pcapStats {
+		stats[device] = s
+	}
+	return stats
+}
+
+// listen captures packets on all network interfaces and pcap files
+// concurrently and parses them
 func listen() {
-	// create handler
-	var handler handler
-
-	// create listener
-	listener := pcap.Listener{
-		PacketHandler: &handler,
-		File:          pcapFile,
-		Device:        pcapDevice,
-		Promisc:       pcapPromisc,
-		Snaplen:       pcapSnaplen,
-		Filter:        pcapFilter,
-	}
-
-	// start listen loop
-	listener.Prepare()
-	listener.Loop()
+	// check for expired entries and update pcap statistics every second
+	timer := time.Second
+
+	// create listeners, unless listening has already been stopped
+	listenerMu.Lock()
+	if stopped {
+		listenerMu.Unlock()
+		return
+	}
+	sources := getPcapSources()
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		handler := &handler{file: source.file}
+		listener := &pcap.Listener{
+			PacketHandler: handler,
+			Timer:         timer,
+			TimerHandler:  handler,
+			File:          source.file,
+			Device:        source.device,
+			Promisc:       pcapPromisc,
+			Snaplen:       pcapSnaplen,
+			Filter:        pcapFilter,
+			Timeout:       time.Duration(pcapTimeout) * time.Second,
+		}
+		listener.Prepare()
+		handler.listener = listener
+
+		// record sources of packets if there are multiple listeners;
+		// the default interface is only known after preparing
+		if len(sources) > 1 {
+			handler.source = listener.Device
+			if listener.File != "" {
+				handler.source = listener.File
+			}
+		}
+		listeners = append(listeners, listener)
+
+		// start listen loop
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listener.Loop()
+			if packetClock != nil {
+				packetClock.Done(listener.File)
+			}
+		}()
+	}
+	listenerMu.Unlock()
+	wg.Wait()
+
+	listenerMu.Lock()
+	listeners = nil
+	listenerMu.Unlock()
+}
+
+// stopListen stops capturing packets
+func stopListen() {
+	listenerMu.Lock()
+	defer listenerMu.Unlock()
+	stopped = true
+	for _, listener := range listeners {
+		if listener.PcapHandle != nil {
+			listener.PcapHandle.Close()
+		}
+	}
 }
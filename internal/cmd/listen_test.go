@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
@@ -61,8 +62,12 @@ func testListenPcapCreateDumpFile() string {
 
 func TestListenPcap(t *testing.T) {
 	// create temporary pcap file
-	pcapFile = testListenPcapCreateDumpFile()
+	pcapFile := testListenPcapCreateDumpFile()
 	defer os.Remove(pcapFile)
+	pcapFiles = listFlag{pcapFile}
+	defer func() {
+		pcapFiles = nil
+	}()
 
 	// handle packet
 	devices = dev.DeviceMap{}
@@ -91,8 +96,12 @@ func TestListenPcapFilter(t *testing.T) {
 	var buf bytes.Buffer
 
 	// create temporary pcap file
-	pcapFile = testListenPcapCreateDumpFile()
+	pcapFile := testListenPcapCreateDumpFile()
 	defer os.Remove(pcapFile)
+	pcapFiles = listFlag{pcapFile}
+	defer func() {
+		pcapFiles = nil
+	}()
 
 	// handle packet with not matching filter
 	devices = dev.DeviceMap{}
@@ -138,3 +147,135 @@ func TestListenPcapFilter(t *testing.T) {
 	// reset filter
 	pcapFilter = ""
 }
+
+func TestListenPcapMultiple(t *testing.T) {
+	// create temporary pcap files
+	file1 := testListenPcapCreateDumpFile()
+	defer os.Remove(file1)
+	file2 := testListenPcapCreateDumpFile()
+	defer os.Remove(file2)
+	pcapFiles = listFlag{file1, file2}
+	defer func() {
+		pcapFiles = nil
+	}()
+
+	// handle packets in both files
+	devices = dev.DeviceMap{}
+	pkt.SetDevices(&devices)
+	listen()
+
+	// check results, device should be seen in both files
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	device := devices.Get(mac)
+	if device == nil {
+		t.Fatalf("got = nil; want %s", mac)
+	}
+	if device.Packets != 2 || device.Interfaces.Len() != 2 ||
+		device.Interfaces.Get(file1) == nil ||
+		device.Interfaces.Get(file2) == nil {
+		t.Errorf("got = %d, %d; want 2, 2", device.Packets,
+			device.Interfaces.Len())
+	}
+}
+
+func TestListenHandleTimer(t *testing.T) {
+	// prepare device table with an old device
+	devices = dev.DeviceMap{}
+	mac, err := net.ParseMAC("00:00:5e:00:53:01")
+	if err != nil {
+		log.Fatal(err)
+	}
+	linkAddr := layers.NewMACEndpoint(mac)
+	devices.Add(linkAddr).SetTimestamp(time.Now().Add(-time.Hour))
+
+	// handle timer event and check results
+	expire = 60
+	defer func() {
+		expire = 0
+	}()
+	var h handler
+	h.HandleTimer()
+	if devices.Get(linkAddr) != nil {
+		t.Errorf("devices.Get() != nil; want nil")
+	}
+}
+
+// testListenPcapCreateTimedFile creates a pcap file with a packet from each
+// mac address at the timestamp with the same index
+func testListenPcapCreateTimedFile(t *testing.T, macs []net.HardwareAddr,
+	timestamps []time.Time) string {
+	file, err := os.CreateTemp(t.TempDir(), "listen.pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	w := pcapgo.NewWriter(file)
+	w.WriteFileHeader(65536, layers.LinkTypeEthernet)
+	for i, mac := range macs {
+		buf := gopacket.NewSerializeBuffer()
+		err := gopacket.SerializeLayers(buf,
+			gopacket.SerializeOptions{FixLengths: true},
+			&layers.Ethernet{SrcMAC: mac, DstMAC: mac})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WritePacket(gopacket.CaptureInfo{
+			Timestamp:     timestamps[i],
+			CaptureLength: len(buf.Bytes()),
+			Length:        len(buf.Bytes()),
+		}, buf.Bytes())
+	}
+	return file.Name()
+}
+
+func TestListenPcapExpire(t *testing.T) {
+	// create pcap file with an old and a new device
+	mac1 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}
+	mac2 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	pcapFiles = listFlag{testListenPcapCreateTimedFile(t,
+		[]net.HardwareAddr{mac1, mac2},
+		[]time.Time{timestamp, timestamp.Add(90 * time.Second)})}
+	expire = 60
+	packetClock = &dev.SourcesClock{}
+	dev.SetClock(packetClock)
+	defer func() {
+		pcapFiles = nil
+		expire = 0
+		packetClock = nil
+		lastExpire = time.Time{}
+		dev.SetClock(nil)
+	}()
+
+	// read file, old device should expire on the packet timeline
+	devices = dev.DeviceMap{}
+	pkt.SetDevices(&devices)
+	listen()
+	if devices.Get(layers.NewMACEndpoint(mac1)) != nil ||
+		devices.Get(layers.NewMACEndpoint(mac2)) == nil {
+		t.Errorf("got = %d devices; want only %s",
+			devices.Stats().Devices, mac2)
+	}
+}
+
+func TestUsePacketClock(t *testing.T) {
+	defer func() {
+		pcapFiles = nil
+		pcapDevices = nil
+	}()
+	for _, test := range []struct {
+		devices, files listFlag
+		want           bool
+	}{
+		{nil, nil, false},
+		{listFlag{"eth0"}, nil, false},
+		{nil, listFlag{"file.pcap"}, true},
+		{listFlag{"eth0"}, listFlag{"file.pcap"}, false},
+	} {
+		pcapDevices, pcapFiles = test.devices, test.files
+		if got := usePacketClock(); got != test.want {
+			t.Errorf("%v, %v: got = %t; want %t", test.devices,
+				test.files, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hwipl/listnd/internal/dev"
+	"github.com/hwipl/listnd/internal/pkt"
+)
+
+// metricLabelEscaper escapes label values in the prometheus text format
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n",
+	`\n`)
+
+// metric is a prometheus metric with its samples
+type metric struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+// metricSample is a sample of a metric with label names and values in
+// alternating order
+type metricSample struct {
+	labels []string
+	value  int
+}
+
+// add adds a sample with value and labels to the metric
+func (m *metric) add(value int, labels ...string) {
+	m.samples = append(m.samples, metricSample{labels, value})
+}
+
+// write writes the metric in the prometheus text format to w
+func (m *metric) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.typ)
+	for _, s := range m.samples {
+		var labels []string
+		for i := 0; i+1 < len(s.labels); i += 2 {
+			labels = append(labels, fmt.Sprintf(`%s="%s"`,
+				s.labels[i], metricLabelEscaper.Replace(s.labels[i+1])))
+		}
+		if len(labels) == 0 {
+			fmt.Fprintf(w, "%s %d\n", m.name, s.value)
+			continue
+		}
+		fmt.Fprintf(w, "%s{%s} %d\n", m.name, strings.Join(labels, ","),
+			s.value)
+	}
+}
+
+// getMetrics returns the metrics of the device table, the packet parser and
+// pcap
+func getMetrics() []*metric {
+	packets := &metric{
+		name: "listnd_packets_total",
+		help: "Total number of parsed packets.",
+		typ:  "counter",
+	}
+	devs := &metric{
+		name: "listnd_devices",
+		help: "Number of devices in the device table.",
+		typ:  "gauge",
+	}
+	alerts := &metric{
+		name: "listnd_alerts",
+		help: "Number of alerts.",
+		typ:  "gauge",
+	}
+	props := &metric{
+		name: "listnd_devices_by_property",
+		help: "Number of devices with a property.",
+		typ:  "gauge",
+	}
+	devPackets := &metric{
+		name: "listnd_device_packets",
+		help: "Number of packets sent by a device in the device table.",
+		typ:  "gauge",
+	}
+	vnetPackets := &metric{
+		name: "listnd_vnet_packets",
+		help: "Number of packets of devices in the device table in a " +
+			"VLAN, VXLAN or GENEVE network.",
+		typ: "gauge",
+	}
+	parseErrors := &metric{
+		name: "listnd_parse_errors_total",
+		help: "Number of packets with decoding errors by last " +
+			"decoded layer.",
+		typ: "counter",
+	}
+
+	// device table and parser metrics
+	devices.Lock()
+	stats := devices.Stats()
+	packets.add(stats.Packets)
+	devs.add(stats.Devices)
+	alerts.add(stats.Alerts)
+	for _, p := range dev.Properties {
+		props.add(stats.Properties[p], "property", p)
+	}
+	for _, device := range devices.Devices(nil) {
+		devPackets.add(device.Packets, "mac", device.MAC.String(),
+			"vlan", fmt.Sprint(device.VLAN))
+	}
+	for _, vnet := range devices.VNetPackets() {
+		vnetPackets.add(vnet.Packets, "type", vnet.Type, "id",
+			fmt.Sprint(vnet.ID))
+	}
+	errs := pkt.ParseErrors()
+	devices.Unlock()
+	var layers []string
+	for layer := range errs {
+		layers = append(layers, layer)
+	}
+	sort.Strings(layers)
+	for _, layer := range layers {
+		parseErrors.add(errs[layer], "layer", layer)
+	}
+
+	// pcap metrics
+	received := &metric{
+		name: "listnd_pcap_packets_received_total",
+		help: "Number of packets received by pcap on an interface.",
+		typ:  "counter",
+	}
+	dropped := &metric{
+		name: "listnd_pcap_packets_dropped_total",
+		help: "Number of packets dropped by pcap on an interface.",
+		typ:  "counter",
+	}
+	ifDropped := &metric{
+		name: "listnd_pcap_packets_if_dropped_total",
+		help: "Number of packets dropped by the network interface.",
+		typ:  "counter",
+	}
+	ifStats := getPcapStats()
+	var ifaces []string
+	for iface := range ifStats {
+		ifaces = append(ifaces, iface)
+	}
+	sort.Strings(ifaces)
+	for _, iface := range ifaces {
+		s := ifStats[iface]
+		received.add(s.PacketsReceived, "interface", iface)
+		dropped.add(s.PacketsDropped, "interface", iface)
+		ifDropped.add(s.PacketsIfDropped, "interface", iface)
+	}
+
+	return []*metric{packets, devs, alerts, props, devPackets, vnetPackets,
+		parseErrors, received, dropped, ifDropped}
+}
+
+// handleMetrics sends the metrics in the prometheus text format to http
+// clients
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; "+
+		"charset=utf-8")
+	for _, m := range getMetrics() {
+		m.write(w)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+	gopcap "github.com/gopacket/gopacket/pcap"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+func TestMetrics(t *testing.T) {
+	devices = dev.DeviceMap{}
+	devices.Packets = 5
+	device := devices.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	device.Packets = 3
+	device.Router.Enable()
+	vlan := device.VLANs.Add(10, "VLAN")
+	vlan.Packets = 2
+	pcapStats["eth0"] = gopcap.Stats{
+		PacketsReceived:  7,
+		PacketsDropped:   1,
+		PacketsIfDropped: 0,
+	}
+	defer delete(pcapStats, "eth0")
+
+	rec := testAPIRequest("GET", "/metrics", "")
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got,
+		"text/plain; version=0.0.4") {
+		t.Errorf("content type = %s; want text/plain; version=0.0.4",
+			got)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE listnd_packets_total counter\n" +
+			"listnd_packets_total 5\n",
+		"listnd_devices 1\n",
+		"listnd_devices_by_property{property=\"router\"} 1\n",
+		"listnd_devices_by_property{property=\"bridge\"} 0\n",
+		"# TYPE listnd_device_packets gauge\n",
+		"listnd_device_packets{mac=\"00:00:5e:00:53:01\"," +
+			"vlan=\"0\"} 3\n",
+		"# TYPE listnd_vnet_packets gauge\n",
+		"listnd_vnet_packets{type=\"VLAN\",id=\"10\"} 2\n",
+		"# TYPE listnd_parse_errors_total counter\n",
+		"listnd_pcap_packets_received_total{interface=\"eth0\"} 7\n",
+		"listnd_pcap_packets_dropped_total{interface=\"eth0\"} 1\n",
+		"listnd_pcap_packets_if_dropped_total{interface=\"eth0\"} 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricLabelEscaping(t *testing.T) {
+	m := &metric{name: "test", help: "Test.", typ: "gauge"}
+	m.add(1, "label", "a\"b\\c\nd")
+	var buf strings.Builder
+	m.write(&buf)
+	want := "# HELP test Test.\n" +
+		"# TYPE test gauge\n" +
+		"test{label=\"a\\\"b\\\\c\\nd\"} 1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
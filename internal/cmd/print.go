@@ -3,8 +3,12 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/hwipl/listnd/internal/dev"
 )
 
 var (
@@ -18,10 +22,50 @@ func debug(text string) {
 	}
 }
 
+// isValidFormat checks if the output format f is supported
+func isValidFormat(f string) bool {
+	switch f {
+	case "text", "json":
+		return true
+	}
+	return false
+}
+
+// splitList splits the comma-separated list s into its non-empty elements
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// alertLogger is an alert sink that logs new alerts
+type alertLogger struct{}
+
+// HandleAlert logs the new alert
+func (a alertLogger) HandleAlert(alert *dev.Alert) {
+	log.Printf("Alert: %s", alert)
+}
+
+// printDevices prints the devices matching filter flt in the device table
+// to w in output format f
+func printDevices(w io.Writer, f string, flt *dev.Filter) {
+	if f == "json" {
+		if err := devices.PrintJSONFilter(w, flt); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	devices.PrintFilter(w, flt)
+}
+
 // printTable prints the device table
 func printTable() {
 	devices.Lock()
-	devices.Print(os.Stdout)
+	printDevices(os.Stdout, format, &filter)
 	devices.Unlock()
 }
 
@@ -55,3 +55,14 @@ func TestPrintTable(t *testing.T) {
 		t.Errorf("got = %s; want %s", got, want)
 	}
 }
+
+func TestSplitList(t *testing.T) {
+	want := []string{"00:00:5e:00:53:01@10", "192.0.2.1"}
+	got := splitList(" 00:00:5e:00:53:01@10, ,192.0.2.1,")
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("got = %v; want []", got)
+	}
+}
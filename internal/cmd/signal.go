@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// reloadOUI reloads the oui database file and updates the vendors of all
+// devices in the device table
+func reloadOUI() error {
+	db, err := dev.LoadOUIDB(ouiFile)
+	if err != nil {
+		return err
+	}
+	devices.Lock()
+	dev.SetOUIDB(db)
+	devices.UpdateVendors()
+	devices.Unlock()
+	return nil
+}
+
+// reloadConfig reloads the oui database file and the http credentials file;
+// if reloading a file fails, the current configuration of it is kept
+func reloadConfig() {
+	reloaded := true
+	if err := reloadOUI(); err != nil {
+		log.Printf("error reloading oui file: %v", err)
+		reloaded = false
+	}
+	if httpAuthConfig != nil {
+		if err := httpAuthConfig.reload(httpAuthFile); err != nil {
+			log.Printf("error reloading http auth file: %v", err)
+			reloaded = false
+		}
+	}
+	if reloaded {
+		log.Println("Reloaded configuration")
+	}
+}
+
+// handleSignal handles signal s: SIGINT and SIGTERM stop listening, SIGHUP
+// reloads the configuration and SIGUSR1 prints the device table
+func handleSignal(s os.Signal) {
+	debug(fmt.Sprintf("Received signal: %v", s))
+	switch s {
+	case syscall.SIGINT, syscall.SIGTERM:
+		// restore default behavior, so another signal kills listnd
+		// if shutting down hangs
+		signal.Reset(syscall.SIGINT, syscall.SIGTERM)
+		stopListen()
+	case syscall.SIGHUP:
+		reloadConfig()
+	case syscall.SIGUSR1:
+		printTable()
+	}
+}
+
+// handleSignals starts handling signals and returns a function that stops
+// handling them
+func handleSignals() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM,
+		syscall.SIGHUP, syscall.SIGUSR1)
+	go func() {
+		for s := range signals {
+			handleSignal(s)
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
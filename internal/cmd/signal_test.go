@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+	"github.com/hwipl/listnd/internal/pkt"
+)
+
+func TestHandleSignal(t *testing.T) {
+	// reload configuration, vendors should be updated
+	devices = dev.DeviceMap{}
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	devices.Add(mac)
+	handleSignal(syscall.SIGHUP)
+	if v := devices.Get(mac).Vendor; v != "ICANN, IANA Department" {
+		t.Errorf("vendor = %s; want ICANN, IANA Department", v)
+	}
+	dev.SetOUIDB(nil)
+
+	// stop listening, packets should not be read anymore
+	pcapFile := testListenPcapCreateDumpFile()
+	defer os.Remove(pcapFile)
+	pcapFiles = listFlag{pcapFile}
+	defer func() {
+		pcapFiles = nil
+	}()
+	devices = dev.DeviceMap{}
+	pkt.SetDevices(&devices)
+	handleSignal(syscall.SIGTERM)
+	listen()
+	if devices.Get(mac) != nil {
+		t.Errorf("got = %v; want nil", devices.Get(mac))
+	}
+	stopped = false
+}
+
+func TestHandleSignalReloadHTTPAuth(t *testing.T) {
+	var err error
+	httpAuthFile = testAuthFile(t, "admin token admintoken\n"+
+		"read token readtoken\n")
+	httpAuthConfig, err = loadHTTPAuth(httpAuthFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		httpAuthFile = ""
+		httpAuthConfig = nil
+		dev.SetOUIDB(nil)
+	}()
+	devices = dev.DeviceMap{}
+	handler := newHTTPHandler()
+
+	// request sends a request with token to the handler and returns the
+	// status code
+	request := func(token string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/v1/stats", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if got := request("readtoken"); got != http.StatusOK {
+		t.Errorf("got = %d; want %d", got, http.StatusOK)
+	}
+
+	// revoke token and reload, token should be rejected
+	err = os.WriteFile(httpAuthFile, []byte("admin token admintoken\n"),
+		0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handleSignal(syscall.SIGHUP)
+	if got := request("readtoken"); got != http.StatusUnauthorized {
+		t.Errorf("got = %d; want %d", got, http.StatusUnauthorized)
+	}
+
+	// reload invalid file, current credentials should be kept
+	err = os.WriteFile(httpAuthFile, []byte("invalid\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handleSignal(syscall.SIGHUP)
+	if got := request("admintoken"); got != http.StatusOK {
+		t.Errorf("got = %d; want %d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+)
+
+// loadState restores the device table from the state file if it exists
+func loadState() {
+	devices.Lock()
+	defer devices.Unlock()
+	err := devices.LoadState(stateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		debug("State file does not exist yet")
+		return
+	}
+	if err != nil {
+		log.Fatalf("error loading state file: %v", err)
+	}
+}
+
+// saveState writes the device table to the state file
+func saveState() {
+	if stateFile == "" {
+		return
+	}
+	devices.Lock()
+	err := devices.SaveState(stateFile)
+	devices.Unlock()
+	if err != nil {
+		log.Printf("error saving state file: %v", err)
+	}
+}
+
+// saveStatePeriodically writes the device table to the state file
+// periodically
+func saveStatePeriodically() {
+	go func() {
+		for {
+			time.Sleep(time.Duration(stateInterval) * time.Second)
+			saveState()
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+func TestState(t *testing.T) {
+	stateFile = filepath.Join(t.TempDir(), "state.json")
+	defer func() {
+		stateFile = ""
+	}()
+
+	// missing state file should be ignored
+	devices = dev.DeviceMap{}
+	loadState()
+
+	// save and restore device table
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	devices.Packets = 1
+	devices.Add(mac).Packets = 1
+	saveState()
+	devices = dev.DeviceMap{}
+	loadState()
+	if devices.Packets != 1 || devices.Get(mac) == nil ||
+		devices.Get(mac).Packets != 1 {
+		t.Errorf("got = %d, %v; want = 1, %s", devices.Packets,
+			devices.Get(mac), mac)
+	}
+}
@@ -0,0 +1,184 @@
+package dev
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+// ipv6 address kinds
+const (
+	AddrKindEUI64        = "EUI-64"
+	AddrKindPrivacy      = "privacy"
+	AddrKindStableOpaque = "stable-opaque"
+	AddrKindDHCPv6       = "DHCPv6"
+)
+
+// eui64MAC returns the mac address embedded in the EUI-64 interface
+// identifier of the ipv6 address ip
+func eui64MAC(ip net.IP) (net.HardwareAddr, bool) {
+	ip = ip.To16()
+	if ip == nil || ip.To4() != nil || ip[11] != 0xff || ip[12] != 0xfe {
+		return nil, false
+	}
+	mac := net.HardwareAddr{ip[8] ^ 0x02, ip[9], ip[10], ip[13], ip[14],
+		ip[15]}
+	return mac, true
+}
+
+// isManualIID checks if the interface identifier of the ipv6 address ip
+// looks manually configured, e.g., ::1 or ::100
+func isManualIID(ip net.IP) bool {
+	return bytes.Equal(ip.To16()[8:14], make([]byte, 6))
+}
+
+// classifyAddr sets the kind of the new unicast ipv6 address a of the
+// device once when it is added: EUI-64 addresses, and addresses with random
+// interface identifiers; of those, link-local addresses and the first random
+// address in a /64 prefix are considered stable-opaque, later ones privacy
+// addresses; DHCPv6 assigned addresses are set with SetDHCPv6. This is a
+// best-effort heuristic, e.g., a privacy address can be seen before the
+// stable-opaque address of the prefix
+func (d *DeviceInfo) classifyAddr(a *AddrInfo) {
+	if a.Addr.EndpointType() != layers.EndpointIPv6 {
+		return
+	}
+	ip := net.IP(a.Addr.Raw())
+	if ip.IsMulticast() {
+		return
+	}
+	if mac, ok := eui64MAC(ip); ok {
+		a.Kind = AddrKindEUI64
+		if embedded := layers.NewMACEndpoint(mac); embedded != d.MAC {
+			a.EmbeddedMAC = embedded
+		}
+		return
+	}
+	if isManualIID(ip) {
+		return
+	}
+	a.Kind = AddrKindStableOpaque
+	if ip.IsLinkLocalUnicast() {
+		return
+	}
+
+	// random address, the first one in a prefix is stable
+	prefix := a.Addr.Raw()[:8]
+	for _, b := range d.UCasts.m {
+		if b == a || !bytes.Equal(b.Addr.Raw()[:8], prefix) {
+			continue
+		}
+		if b.Kind == AddrKindStableOpaque || b.Kind == AddrKindPrivacy {
+			a.Kind = AddrKindPrivacy
+			return
+		}
+	}
+}
+
+// embeddedAddr is an EUI-64 address of a device that embeds the mac address
+// of another device
+type embeddedAddr struct {
+	device  *DeviceInfo
+	address gopacket.Endpoint
+}
+
+// embeddedKey returns the key of the device with the mac address embedded in
+// an address of device
+func embeddedKey(device *DeviceInfo, embedded gopacket.Endpoint) deviceKey {
+	if !vlanMode {
+		return deviceKey{mac: embedded}
+	}
+	return deviceKey{vlan: device.VLAN, mac: embedded}
+}
+
+// isCurrent checks if the device and its address that embeds the mac address
+// of another device are still in the device table
+func (d *DeviceMap) isCurrent(e embeddedAddr) bool {
+	if d.m[deviceKey{e.device.VLAN, e.device.MAC}] != e.device {
+		return false
+	}
+	a := e.device.UCasts.Get(e.address)
+	return a != nil && a.EmbeddedMAC != (gopacket.Endpoint{})
+}
+
+// addEmbeddedMACAlert raises an embedded-mac alert for the device with the
+// address that embeds the mac address of another device
+func (d *DeviceMap) addEmbeddedMACAlert(e embeddedAddr,
+	embedded gopacket.Endpoint) {
+	vlan := d.vlan
+	if vlanMode {
+		vlan = e.device.VLAN
+	}
+	d.Alerts.Add(AlertEmbeddedMAC, e.device.MAC, vlan, embedded.String(),
+		fmt.Sprintf("address %s embeds mac of device %s, routing "+
+			"or proxying", e.address, embedded), d.packetTime)
+}
+
+// addEmbeddedMAC adds the new address a of the device to the index of
+// addresses that embed the mac address of another device and raises an
+// embedded-mac alert if the other device is in the device table, i.e., the
+// device routes or proxies traffic for the other device
+func (d *DeviceMap) addEmbeddedMAC(device *DeviceInfo, a *AddrInfo) {
+	if a.EmbeddedMAC == (gopacket.Endpoint{}) {
+		return
+	}
+	if d.embedded == nil {
+		d.embedded = make(map[deviceKey][]embeddedAddr)
+	}
+	key := embeddedKey(device, a.EmbeddedMAC)
+	e := embeddedAddr{device, a.Addr}
+	d.embedded[key] = append(d.embedded[key], e)
+	if d.m[key] != nil {
+		d.addEmbeddedMACAlert(e, a.EmbeddedMAC)
+	}
+}
+
+// checkEmbeddedMACs raises embedded-mac alerts for all other devices with
+// addresses that embed the mac address of the new device
+func (d *DeviceMap) checkEmbeddedMACs(key deviceKey) {
+	for _, e := range d.embedded[key] {
+		if d.isCurrent(e) {
+			d.addEmbeddedMACAlert(e, key.mac)
+		}
+	}
+}
+
+// expireEmbeddedMACs removes addresses of removed devices and removed
+// addresses from the index of embedded mac addresses
+func (d *DeviceMap) expireEmbeddedMACs() {
+	for key, addrs := range d.embedded {
+		var current []embeddedAddr
+		for _, e := range addrs {
+			if d.isCurrent(e) {
+				current = append(current, e)
+			}
+		}
+		if len(current) == 0 {
+			delete(d.embedded, key)
+			continue
+		}
+		d.embedded[key] = current
+	}
+}
+
+// indexEmbeddedMACs rebuilds the index of embedded mac addresses from all
+// devices, e.g., after loading the device table
+func (d *DeviceMap) indexEmbeddedMACs() {
+	d.embedded = nil
+	for _, device := range d.m {
+		for _, a := range device.UCasts.m {
+			if a.EmbeddedMAC == (gopacket.Endpoint{}) {
+				continue
+			}
+			if d.embedded == nil {
+				d.embedded = make(map[deviceKey][]embeddedAddr)
+			}
+			key := embeddedKey(device, a.EmbeddedMAC)
+			d.embedded[key] = append(d.embedded[key],
+				embeddedAddr{device, a.Addr})
+		}
+	}
+}
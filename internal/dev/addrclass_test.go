@@ -0,0 +1,141 @@
+package dev
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestEUI64MAC(t *testing.T) {
+	mac, ok := eui64MAC(net.ParseIP("fe80::200:5eff:fe00:5301"))
+	if !ok || mac.String() != "00:00:5e:00:53:01" {
+		t.Errorf("got = %s, %t; want 00:00:5e:00:53:01, true", mac, ok)
+	}
+	for _, ip := range []string{"fe80::1", "192.0.2.1"} {
+		if _, ok := eui64MAC(net.ParseIP(ip)); ok {
+			t.Errorf("%s: got = true; want false", ip)
+		}
+	}
+}
+
+func TestDeviceInfoClassifyAddr(t *testing.T) {
+	var m DeviceMap
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	d := m.Add(layers.NewMACEndpoint(mac))
+	add := func(ip string) *AddrInfo {
+		return d.UCasts.Add(layers.NewIPEndpoint(net.ParseIP(ip)))
+	}
+	eui := add("fe80::200:5eff:fe00:5301")
+	foreign := add("2001:db8::200:5eff:fe00:5302")
+	ll := add("fe80::a1b2:c3d4:e5f6:1234")
+	stable := add("2001:db8::a1b2:c3d4:e5f6:1234")
+	privacy := add("2001:db8::1b2c:3d4e:5f61:2345")
+	dhcp := add("2001:db8::1:100")
+	dhcp.SetDHCPv6()
+	manual := add("2001:db8::1")
+	v4 := add("192.0.2.1")
+
+	for _, test := range []struct {
+		a    *AddrInfo
+		want string
+	}{
+		{eui, AddrKindEUI64},
+		{foreign, AddrKindEUI64},
+		{ll, AddrKindStableOpaque},
+		{stable, AddrKindStableOpaque},
+		{privacy, AddrKindPrivacy},
+		{dhcp, AddrKindDHCPv6},
+		{manual, ""},
+		{v4, ""},
+	} {
+		if test.a.Kind != test.want {
+			t.Errorf("%s: got = %s; want %s", test.a.Addr,
+				test.a.Kind, test.want)
+		}
+	}
+
+	// privacy address stays privacy address after stable address is
+	// removed
+	d.UCasts.Del(stable.Addr)
+	var buf bytes.Buffer
+	m.Print(&buf)
+	if privacy.Kind != AddrKindPrivacy {
+		t.Errorf("got = %s; want %s", privacy.Kind, AddrKindPrivacy)
+	}
+
+	// check embedded mac of foreign eui-64 address
+	if eui.EmbeddedMAC != (gopacket.Endpoint{}) {
+		t.Errorf("got = %s; want []", eui.EmbeddedMAC)
+	}
+	if foreign.EmbeddedMAC.String() != "00:00:5e:00:53:02" {
+		t.Errorf("got = %s; want 00:00:5e:00:53:02",
+			foreign.EmbeddedMAC)
+	}
+	want := "IP: 2001:db8::200:5eff:fe00:5302 [EUI-64 of " +
+		"00:00:5e:00:53:02] (age: 0, pkts: 0)"
+	foreign.SetTimestamp(time.Now())
+	if got := foreign.String(); got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestDeviceMapCheckEmbeddedMAC(t *testing.T) {
+	mac1, _ := net.ParseMAC("00:00:5e:00:53:01")
+	mac2, _ := net.ParseMAC("00:00:5e:00:53:02")
+	mac3, _ := net.ParseMAC("00:00:5e:00:53:03")
+	ip := func(s string) gopacket.Endpoint {
+		return layers.NewIPEndpoint(net.ParseIP(s))
+	}
+
+	// own and unknown embedded macs do not raise alerts
+	var d DeviceMap
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	d.SetPacketTime(timestamp)
+	router := d.Add(layers.NewMACEndpoint(mac1))
+	router.UCasts.Add(ip("fe80::200:5eff:fe00:5301"))
+	router.UCasts.Add(ip("2001:db8::200:5eff:fe00:5302"))
+	if d.Alerts.Len() != 0 {
+		t.Fatalf("got = %d; want 0", d.Alerts.Len())
+	}
+
+	// embedded mac of device added later
+	d.Add(layers.NewMACEndpoint(mac2))
+	want := "embedded-mac: device 00:00:5e:00:53:01, vlan 0: address " +
+		"2001:db8::200:5eff:fe00:5302 embeds mac of device " +
+		"00:00:5e:00:53:02, routing or proxying"
+	alerts := d.Alerts.Get()
+	if len(alerts) != 1 || alerts[0].String() != want ||
+		alerts[0].FirstSeen != timestamp {
+		t.Fatalf("got = %v; want %s", alerts, want)
+	}
+
+	// embedded mac of other known device in new address, new alert
+	d.Add(layers.NewMACEndpoint(mac3))
+	router.UCasts.Add(ip("2001:db8::200:5eff:fe00:5303"))
+	want = "embedded-mac: device 00:00:5e:00:53:01, vlan 0: address " +
+		"2001:db8::200:5eff:fe00:5303 embeds mac of device " +
+		"00:00:5e:00:53:03, routing or proxying"
+	alerts = d.Alerts.Get()
+	if len(alerts) != 2 || alerts[1].Count != 1 ||
+		alerts[1].String() != want {
+		t.Errorf("got = %v; want %s", alerts, want)
+	}
+
+	// removed addresses are removed from the index and do not raise
+	// alerts for new devices
+	mac4, _ := net.ParseMAC("00:00:5e:00:53:04")
+	router.UCasts.Add(ip("2001:db8::200:5eff:fe00:5304"))
+	router.UCasts.Del(ip("2001:db8::200:5eff:fe00:5304"))
+	d.expireEmbeddedMACs()
+	if len(d.embedded) != 2 {
+		t.Errorf("got = %d; want 2", len(d.embedded))
+	}
+	d.Add(layers.NewMACEndpoint(mac4))
+	if d.Alerts.Len() != 2 {
+		t.Errorf("got = %d; want 2", d.Alerts.Len())
+	}
+}
@@ -1,6 +1,7 @@
 package dev
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/gopacket/gopacket"
@@ -10,8 +11,18 @@ import (
 // AddrInfo stores an ip or mac address of a device on the network
 type AddrInfo struct {
 	TimeInfo
-	Addr    gopacket.Endpoint
-	Packets int
+	Addr        gopacket.Endpoint
+	Packets     int
+	DHCPv6      bool
+	Kind        string
+	EmbeddedMAC gopacket.Endpoint
+}
+
+// SetDHCPv6 marks the address as assigned in a DHCPv6 reply
+func (a *AddrInfo) SetDHCPv6() {
+	a.DHCPv6 = true
+	a.Kind = AddrKindDHCPv6
+	a.EmbeddedMAC = gopacket.Endpoint{}
 }
 
 // String converts address info to a string
@@ -23,5 +34,66 @@ func (a *AddrInfo) String() string {
 		aFmt = "IP: %-40s (age: %.f, pkts: %d)"
 	}
 
-	return fmt.Sprintf(aFmt, a.Addr, a.Age(), a.Packets)
+	addr := a.Addr.String()
+	switch {
+	case a.EmbeddedMAC != (gopacket.Endpoint{}):
+		addr += " [" + a.Kind + " of " + a.EmbeddedMAC.String() + "]"
+	case a.Kind != "":
+		addr += " [" + a.Kind + "]"
+	}
+	return fmt.Sprintf(aFmt, addr, a.Age(), a.Packets)
+}
+
+// MarshalJSON converts address info to json
+func (a *AddrInfo) MarshalJSON() ([]byte, error) {
+	var embedded string
+	if a.EmbeddedMAC != (gopacket.Endpoint{}) {
+		embedded = a.EmbeddedMAC.String()
+	}
+	return json.Marshal(struct {
+		Addr string `json:"addr"`
+		TimeInfo
+		Packets     int    `json:"packets"`
+		Kind        string `json:"kind,omitempty"`
+		EmbeddedMAC string `json:"embedded_mac,omitempty"`
+	}{
+		Addr:        a.Addr.String(),
+		TimeInfo:    a.TimeInfo,
+		Packets:     a.Packets,
+		Kind:        a.Kind,
+		EmbeddedMAC: embedded,
+	})
+}
+
+// UnmarshalJSON restores address info from json
+func (a *AddrInfo) UnmarshalJSON(data []byte) error {
+	var addr struct {
+		Addr string `json:"addr"`
+		TimeInfo
+		Packets     int    `json:"packets"`
+		Kind        string `json:"kind"`
+		EmbeddedMAC string `json:"embedded_mac"`
+	}
+	if err := json.Unmarshal(data, &addr); err != nil {
+		return err
+	}
+	endpoint, err := parseEndpoint(addr.Addr)
+	if err != nil {
+		return err
+	}
+	var embedded gopacket.Endpoint
+	if addr.EmbeddedMAC != "" {
+		if embedded, err = parseEndpoint(addr.EmbeddedMAC); err != nil {
+			return err
+		}
+	}
+	*a = AddrInfo{
+		TimeInfo:    addr.TimeInfo,
+		Addr:        endpoint,
+		Packets:     addr.Packets,
+		DHCPv6:      addr.Kind == AddrKindDHCPv6,
+		Kind:        addr.Kind,
+		EmbeddedMAC: embedded,
+	}
+	return nil
 }
@@ -1,9 +1,12 @@
 package dev
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
+	"sort"
+	"time"
 
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
@@ -45,6 +48,29 @@ func isValidAddr(address gopacket.Endpoint) bool {
 type AddrMap struct {
 	Name string
 	m    map[gopacket.Endpoint]*AddrInfo
+
+	// events emitted when addresses are added or removed
+	events   *eventEmitter
+	addEvent string
+	delEvent string
+
+	// added is called when a new address is added
+	added func(a *AddrInfo)
+}
+
+// setEvents sets the emitter for events of type add and del that are
+// emitted when addresses are added or removed
+func (a *AddrMap) setEvents(events *eventEmitter, add, del string) {
+	a.events = events
+	a.addEvent = add
+	a.delEvent = del
+}
+
+// emit emits an event of type typ for address if events are enabled
+func (a *AddrMap) emit(typ string, address gopacket.Endpoint) {
+	if a.events != nil && typ != "" {
+		a.events.emit(typ, address.String())
+	}
 }
 
 // Add adds address to the AddrMap and returns the address info
@@ -65,6 +91,10 @@ func (a *AddrMap) Add(address gopacket.Endpoint) *AddrInfo {
 			Addr: address,
 		}
 		a.m[address] = &addr
+		a.emit(a.addEvent, address)
+		if a.added != nil {
+			a.added(&addr)
+		}
 	}
 	return a.m[address]
 }
@@ -88,6 +118,18 @@ func (a *AddrMap) Del(address gopacket.Endpoint) {
 	if a.m[address] != nil {
 		debug("Deleting address entry")
 		delete(a.m, address)
+		a.emit(a.delEvent, address)
+	}
+}
+
+// Expire removes all address infos not seen within timeout
+func (a *AddrMap) Expire(timeout time.Duration) {
+	for address, addr := range a.m {
+		if addr.IsExpired(timeout) {
+			debug("Expiring address entry")
+			delete(a.m, address)
+			a.emit(a.delEvent, address)
+		}
 	}
 }
 
@@ -101,3 +143,40 @@ func (a *AddrMap) Print(w io.Writer) {
 		}
 	}
 }
+
+// MarshalJSON converts the address map to a json array sorted by address
+func (a *AddrMap) MarshalJSON() ([]byte, error) {
+	addrs := make([]*AddrInfo, 0, len(a.m))
+	for _, addr := range a.m {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Addr.LessThan(addrs[j].Addr)
+	})
+	return json.Marshal(addrs)
+}
+
+// parseEndpoint parses the mac or ip address s and returns it as endpoint
+func parseEndpoint(s string) (gopacket.Endpoint, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		return layers.NewIPEndpoint(ip), nil
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return gopacket.Endpoint{}, err
+	}
+	return layers.NewMACEndpoint(mac), nil
+}
+
+// UnmarshalJSON restores the address map from a json array
+func (a *AddrMap) UnmarshalJSON(data []byte) error {
+	var addrs []*AddrInfo
+	if err := json.Unmarshal(data, &addrs); err != nil {
+		return err
+	}
+	a.m = make(map[gopacket.Endpoint]*AddrInfo)
+	for _, addr := range addrs {
+		a.m[addr.Addr] = addr
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/gopacket/gopacket/layers"
 )
@@ -140,3 +141,30 @@ func TestAddrMapPrint(t *testing.T) {
 		t.Errorf("got = %s; want %s", got, want)
 	}
 }
+
+func TestAddrMapExpire(t *testing.T) {
+	var a AddrMap
+	var want, got *AddrInfo
+
+	// prepare addresses
+	old := layers.NewIPEndpoint(net.ParseIP("127.0.0.1"))
+	cur := layers.NewIPEndpoint(net.ParseIP("127.0.0.2"))
+	none := layers.NewIPEndpoint(net.ParseIP("127.0.0.3"))
+	a.Add(old).SetTimestamp(time.Now().Add(-2 * time.Minute))
+	a.Add(cur).SetTimestamp(time.Now())
+	a.Add(none)
+
+	// expire and test
+	a.Expire(time.Minute)
+	want = nil
+	got = a.Get(old)
+	if got != want {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+	if a.Get(cur) == nil {
+		t.Errorf("a.Get(cur) = nil; want not nil")
+	}
+	if a.Get(none) == nil {
+		t.Errorf("a.Get(none) = nil; want not nil")
+	}
+}
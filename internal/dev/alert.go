@@ -0,0 +1,106 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gopacket/gopacket"
+)
+
+// alert types
+const (
+	AlertRogueDHCPServer    = "rogue-dhcp-server"
+	AlertRogueRouter        = "rogue-router"
+	AlertUnexpectedPrefix   = "unexpected-prefix"
+	AlertRouterLifetimeZero = "router-lifetime-zero"
+	AlertIPMoved            = "ip-moved"
+	AlertDuplicateIP        = "duplicate-ip"
+	AlertGARPFlood          = "garp-flood"
+	AlertDADFailure         = "dad-failure"
+	AlertEmbeddedMAC        = "embedded-mac"
+)
+
+// Alert is an alert event raised for a device on the network
+type Alert struct {
+	TimeInfo
+	Type    string
+	Device  gopacket.Endpoint
+	VLAN    uint32
+	Subject string
+	Message string
+	Count   int
+}
+
+// String converts the alert to a string
+func (a *Alert) String() string {
+	return fmt.Sprintf("%s: device %s, vlan %d: %s", a.Type, a.Device,
+		a.VLAN, a.Message)
+}
+
+// Print prints the alert to w
+func (a *Alert) Print(w io.Writer) {
+	alertFmt := "Alert: %-41s (age: %.f, count: %d)\n"
+	fmt.Fprintf(w, alertFmt, a.Type, a.Age(), a.Count)
+	fmt.Fprintf(w, "  Device: %s\n", a.Device)
+	if a.VLAN != 0 {
+		fmt.Fprintf(w, "  VLAN: %d\n", a.VLAN)
+	}
+	if a.FirstSeen != (time.Time{}) {
+		fmt.Fprintf(w, "  First Seen: %s\n",
+			a.FirstSeen.Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "  Message: %s\n", a.Message)
+}
+
+// MarshalJSON converts the alert to json
+func (a *Alert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Device  string `json:"device"`
+		VLAN    uint32 `json:"vlan"`
+		Subject string `json:"subject,omitempty"`
+		TimeInfo
+		Count   int    `json:"count"`
+		Message string `json:"message"`
+	}{
+		Type:     a.Type,
+		Device:   a.Device.String(),
+		VLAN:     a.VLAN,
+		Subject:  a.Subject,
+		TimeInfo: a.TimeInfo,
+		Count:    a.Count,
+		Message:  a.Message,
+	})
+}
+
+// UnmarshalJSON restores the alert from json
+func (a *Alert) UnmarshalJSON(data []byte) error {
+	var alert struct {
+		Type    string `json:"type"`
+		Device  string `json:"device"`
+		VLAN    uint32 `json:"vlan"`
+		Subject string `json:"subject"`
+		TimeInfo
+		Count   int    `json:"count"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &alert); err != nil {
+		return err
+	}
+	device, err := parseEndpoint(alert.Device)
+	if err != nil {
+		return err
+	}
+	*a = Alert{
+		TimeInfo: alert.TimeInfo,
+		Type:     alert.Type,
+		Device:   device,
+		VLAN:     alert.VLAN,
+		Subject:  alert.Subject,
+		Message:  alert.Message,
+		Count:    alert.Count,
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package dev
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestAlertPrint(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	// create alert
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	a := Alert{
+		Type:    AlertRogueDHCPServer,
+		Device:  layers.NewMACEndpoint(mac),
+		VLAN:    10,
+		Message: "unauthorized dhcpv4 offer",
+		Count:   2,
+	}
+	a.SetTimestamp(timestamp)
+
+	// test output with packet clock
+	clock := &SourcesClock{}
+	clock.Update("", timestamp.Add(5*time.Second))
+	SetClock(clock)
+	defer SetClock(nil)
+	a.Print(&buf)
+	want = "Alert: rogue-dhcp-server                        " +
+		" (age: 5, count: 2)\n" +
+		"  Device: 00:00:5e:00:53:01\n" +
+		"  VLAN: 10\n" +
+		"  First Seen: 2020-01-02T03:04:05Z\n" +
+		"  Message: unauthorized dhcpv4 offer\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+
+	// test string
+	want = "rogue-dhcp-server: device 00:00:5e:00:53:01, vlan 10: " +
+		"unauthorized dhcpv4 offer"
+	got = a.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
+
+func TestAlertMarshalJSON(t *testing.T) {
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	a := &Alert{
+		Type:    AlertRogueDHCPServer,
+		Device:  layers.NewMACEndpoint(mac),
+		VLAN:    10,
+		Message: "unauthorized dhcpv4 offer",
+		Count:   1,
+	}
+	a.SetTimestamp(timestamp)
+	b, err := a.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"rogue-dhcp-server","device":"00:00:5e:00:53:01",` +
+		`"vlan":10,"first_seen":"2020-01-02T03:04:05Z",` +
+		`"last_seen":"2020-01-02T03:04:05Z","count":1,` +
+		`"message":"unauthorized dhcpv4 offer"}`
+	got := string(b)
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
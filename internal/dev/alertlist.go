@@ -0,0 +1,156 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gopacket/gopacket"
+)
+
+// AlertSink is the interface for receivers of new alerts; sinks are called
+// while the device table is locked, so they must not block
+type AlertSink interface {
+	HandleAlert(alert *Alert)
+}
+
+// alertKey identifies an alert in the alert list
+type alertKey struct {
+	typ     string
+	device  gopacket.Endpoint
+	vlan    uint32
+	subject string
+}
+
+// key returns the key of the alert in the alert list
+func (a *Alert) key() alertKey {
+	return alertKey{a.Type, a.Device, a.VLAN, a.Subject}
+}
+
+// AlertList stores alerts and forwards new alerts to alert sinks
+type AlertList struct {
+	alerts []*Alert
+	m      map[alertKey]*Alert
+	sinks  []AlertSink
+}
+
+// AddSink adds an alert sink that is called for new alerts
+func (a *AlertList) AddSink(sink AlertSink) {
+	a.sinks = append(a.sinks, sink)
+}
+
+// Add adds an alert of type typ for device in vlan about subject, e.g., an
+// ip address, prefix or peer mac address, with message and timestamp to the
+// alert list and returns it; repeated alerts with the same subject only
+// update the existing alert and are not forwarded to the alert sinks again
+func (a *AlertList) Add(typ string, device gopacket.Endpoint, vlan uint32,
+	subject, message string, timestamp time.Time) *Alert {
+	// create map if necessary
+	if a.m == nil {
+		a.m = make(map[alertKey]*Alert)
+	}
+
+	// update existing alert
+	key := alertKey{typ, device, vlan, subject}
+	if alert := a.m[key]; alert != nil {
+		alert.Message = message
+		alert.Count++
+		alert.SetTimestamp(timestamp)
+		return alert
+	}
+
+	// create new alert and forward it to sinks
+	debug("Adding new alert")
+	alert := &Alert{
+		Type:    typ,
+		Device:  device,
+		VLAN:    vlan,
+		Subject: subject,
+		Message: message,
+		Count:   1,
+	}
+	alert.SetTimestamp(timestamp)
+	a.alerts = append(a.alerts, alert)
+	a.m[key] = alert
+	for _, sink := range a.sinks {
+		sink.HandleAlert(alert)
+	}
+	return alert
+}
+
+// Get returns all alerts
+func (a *AlertList) Get() []*Alert {
+	return a.alerts
+}
+
+// Len returns the number of alerts
+func (a *AlertList) Len() int {
+	return len(a.alerts)
+}
+
+// Reset deletes all alerts
+func (a *AlertList) Reset() {
+	a.alerts = nil
+	a.m = nil
+}
+
+// Expire removes all alerts not seen within timeout
+func (a *AlertList) Expire(timeout time.Duration) {
+	var alerts []*Alert
+	for _, alert := range a.alerts {
+		if alert.IsExpired(timeout) {
+			debug("Expiring alert")
+			delete(a.m, alert.key())
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	a.alerts = alerts
+}
+
+// Print prints all alerts to w
+func (a *AlertList) Print(w io.Writer) {
+	if len(a.alerts) == 0 {
+		return
+	}
+	alertsFmt := "===================================" +
+		"===================================\n" +
+		"Alerts: %d\n" +
+		"===================================" +
+		"===================================\n"
+	fmt.Fprintf(w, alertsFmt, len(a.alerts))
+	for _, alert := range a.alerts {
+		alert.Print(w)
+		fmt.Fprintln(w)
+	}
+}
+
+// MarshalJSON converts all alerts to a json array
+func (a *AlertList) MarshalJSON() ([]byte, error) {
+	alerts := a.alerts
+	if alerts == nil {
+		alerts = []*Alert{}
+	}
+	return json.Marshal(alerts)
+}
+
+// UnmarshalJSON restores all alerts from a json array; the alert sinks are
+// kept and not called for the restored alerts
+func (a *AlertList) UnmarshalJSON(data []byte) error {
+	var alerts []*Alert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return err
+	}
+	a.alerts = nil
+	a.m = make(map[alertKey]*Alert)
+	for _, alert := range alerts {
+		key := alert.key()
+		if a.m[key] != nil {
+			continue
+		}
+		a.alerts = append(a.alerts, alert)
+		a.m[key] = alert
+	}
+	return nil
+}
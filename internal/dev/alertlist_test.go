@@ -0,0 +1,142 @@
+package dev
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+// testAlertSink is an alert sink that stores all alerts it receives
+type testAlertSink struct {
+	alerts []*Alert
+}
+
+// HandleAlert stores the alert
+func (s *testAlertSink) HandleAlert(alert *Alert) {
+	s.alerts = append(s.alerts, alert)
+}
+
+func TestAlertListAdd(t *testing.T) {
+	var alerts AlertList
+	var sink testAlertSink
+	alerts.AddSink(&sink)
+
+	// add alert twice, sink should only get it once
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	device := layers.NewMACEndpoint(mac)
+	alerts.Add(AlertRogueDHCPServer, device, 10, "192.0.2.1", "test",
+		timestamp)
+	a := alerts.Add(AlertRogueDHCPServer, device, 10, "192.0.2.1", "test",
+		timestamp.Add(time.Second))
+	if alerts.Len() != 1 || len(sink.alerts) != 1 {
+		t.Errorf("got = %d, %d; want = 1, 1", alerts.Len(),
+			len(sink.alerts))
+	}
+	if a.Count != 2 || a.FirstSeen != timestamp ||
+		a.Timestamp != timestamp.Add(time.Second) {
+		t.Errorf("got = %d, %s, %s; want = 2, %s, %s", a.Count,
+			a.FirstSeen, a.Timestamp, timestamp,
+			timestamp.Add(time.Second))
+	}
+
+	// add alert in other vlan
+	alerts.Add(AlertRogueDHCPServer, device, 20, "192.0.2.1", "test",
+		timestamp)
+	if alerts.Len() != 2 || len(sink.alerts) != 2 {
+		t.Errorf("got = %d, %d; want = 2, 2", alerts.Len(),
+			len(sink.alerts))
+	}
+
+	// add alert with other subject, sink should get it
+	a = alerts.Add(AlertRogueDHCPServer, device, 10, "192.0.2.2", "test",
+		timestamp)
+	if alerts.Len() != 3 || len(sink.alerts) != 3 || a.Count != 1 {
+		t.Errorf("got = %d, %d, %d; want = 3, 3, 1", alerts.Len(),
+			len(sink.alerts), a.Count)
+	}
+
+	// test reset
+	alerts.Reset()
+	if alerts.Len() != 0 {
+		t.Errorf("got = %d; want = 0", alerts.Len())
+	}
+}
+
+func TestAlertListExpire(t *testing.T) {
+	var alerts AlertList
+
+	// add alerts
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	device := layers.NewMACEndpoint(mac)
+	alerts.Add(AlertRogueDHCPServer, device, 10, "", "old", timestamp)
+	alerts.Add(AlertRogueDHCPServer, device, 20, "", "new",
+		timestamp.Add(time.Minute))
+
+	// expire old alert
+	clock := &SourcesClock{}
+	clock.Update("", timestamp.Add(90*time.Second))
+	SetClock(clock)
+	defer SetClock(nil)
+	alerts.Expire(time.Minute)
+	if alerts.Len() != 1 || alerts.Get()[0].Message != "new" {
+		t.Errorf("got = %d; want = 1", alerts.Len())
+	}
+
+	// re-adding expired alert creates a new alert
+	a := alerts.Add(AlertRogueDHCPServer, device, 10, "", "old",
+		timestamp)
+	if a.Count != 1 || alerts.Len() != 2 {
+		t.Errorf("got = %d, %d; want = 1, 2", a.Count, alerts.Len())
+	}
+}
+
+func TestAlertListPrint(t *testing.T) {
+	var alerts AlertList
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	alerts.Print(&buf)
+	if buf.String() != "" {
+		t.Errorf("got = %s; want = ", buf.String())
+	}
+
+	// test with alert
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	alerts.Add(AlertRogueDHCPServer, layers.NewMACEndpoint(mac), 0,
+		"192.0.2.1", "test", time.Time{})
+	alerts.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Alerts: 1\n" +
+		"=================================================" +
+		"=====================\n" +
+		"Alert: rogue-dhcp-server                        " +
+		" (age: -1, count: 1)\n" +
+		"  Device: 00:00:5e:00:53:01\n" +
+		"  Message: test\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+
+	// test json
+	b, err := alerts.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `[{"type":"rogue-dhcp-server","device":"00:00:5e:00:53:01",` +
+		`"vlan":0,"subject":"192.0.2.1",` +
+		`"first_seen":"0001-01-01T00:00:00Z",` +
+		`"last_seen":"0001-01-01T00:00:00Z","count":1,` +
+		`"message":"test"}]`
+	got = string(b)
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package dev
+
+import (
+	"time"
+
+	"github.com/gopacket/gopacket"
+)
+
+// ClaimInfo stores when a MAC address claimed an IP address and how many
+// gratuitous ARP replies it sent for it
+type ClaimInfo struct {
+	TimeInfo
+	MAC       gopacket.Endpoint
+	GARPs     int
+	GARPStart time.Time
+}
+
+// BindingInfo stores the binding of an IP address in a vlan to a MAC address
+// and all MAC addresses that claimed the IP address
+type BindingInfo struct {
+	TimeInfo
+	VLAN   uint32
+	IP     gopacket.Endpoint
+	MAC    gopacket.Endpoint
+	Claims map[gopacket.Endpoint]*ClaimInfo
+}
+
+// Claim returns the claim of the IP address by mac, it is created if
+// necessary
+func (b *BindingInfo) Claim(mac gopacket.Endpoint) *ClaimInfo {
+	if b.Claims == nil {
+		b.Claims = make(map[gopacket.Endpoint]*ClaimInfo)
+	}
+	if b.Claims[mac] == nil {
+		debug("Adding new claim entry")
+		b.Claims[mac] = &ClaimInfo{MAC: mac}
+	}
+	return b.Claims[mac]
+}
+
+// Expire removes all claims not seen within timeout
+func (b *BindingInfo) Expire(timeout time.Duration) {
+	for mac, claim := range b.Claims {
+		if claim.IsExpired(timeout) {
+			debug("Expiring claim entry")
+			delete(b.Claims, mac)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package dev
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestBindingInfoClaim(t *testing.T) {
+	var b BindingInfo
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+
+	// test new claim
+	want := b.Claim(mac)
+	if want == nil || want.MAC != mac {
+		t.Fatalf("got = %v; want claim of %s", want, mac)
+	}
+
+	// test existing claim
+	got := b.Claim(mac)
+	if got != want || len(b.Claims) != 1 {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+}
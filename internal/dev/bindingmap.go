@@ -0,0 +1,71 @@
+package dev
+
+import (
+	"time"
+
+	"github.com/gopacket/gopacket"
+)
+
+// bindingKey identifies an IP address in a vlan; the same IP address can be
+// used in multiple vlans
+type bindingKey struct {
+	vlan uint32
+	ip   gopacket.Endpoint
+}
+
+// BindingMap stores mappings of IP addresses in vlans to their MAC address
+// bindings
+type BindingMap struct {
+	m map[bindingKey]*BindingInfo
+}
+
+// Add adds the IP address ip in vlan to the binding map and returns the
+// binding
+func (b *BindingMap) Add(vlan uint32, ip gopacket.Endpoint) *BindingInfo {
+	// check if address is valid
+	if !isValidAddr(ip) {
+		return nil
+	}
+
+	// create map if necessary
+	if b.m == nil {
+		b.m = make(map[bindingKey]*BindingInfo)
+	}
+	// create table entry if necessary
+	key := bindingKey{vlan, ip}
+	if b.m[key] == nil {
+		debug("Adding new binding entry")
+		b.m[key] = &BindingInfo{VLAN: vlan, IP: ip}
+	}
+	return b.m[key]
+}
+
+// Get returns the binding of IP address ip in vlan
+func (b *BindingMap) Get(vlan uint32, ip gopacket.Endpoint) *BindingInfo {
+	if b.m == nil {
+		return nil
+	}
+	return b.m[bindingKey{vlan, ip}]
+}
+
+// Len returns the number of bindings
+func (b *BindingMap) Len() int {
+	return len(b.m)
+}
+
+// Reset deletes all bindings
+func (b *BindingMap) Reset() {
+	b.m = nil
+}
+
+// Expire removes all bindings and claims not seen within timeout
+func (b *BindingMap) Expire(timeout time.Duration) {
+	for key, binding := range b.m {
+		if binding.IsExpired(timeout) {
+			debug("Expiring binding entry")
+			delete(b.m, key)
+			continue
+		}
+		binding.Expire(timeout)
+	}
+}
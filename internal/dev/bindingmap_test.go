@@ -0,0 +1,69 @@
+package dev
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestBindingMap(t *testing.T) {
+	var b BindingMap
+
+	// test invalid address
+	if b.Add(0, addrUnspecIPv4) != nil {
+		t.Errorf("got binding for unspecified address; want nil")
+	}
+
+	// test add and get
+	ip := layers.NewIPEndpoint(net.IP{192, 0, 2, 1})
+	want := b.Add(0, ip)
+	got := b.Get(0, ip)
+	if got != want || b.Len() != 1 {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+
+	// test same address in other vlan
+	other := b.Add(10, ip)
+	if other == want || other.VLAN != 10 || b.Get(10, ip) != other ||
+		b.Len() != 2 {
+		t.Errorf("got = %p; want new binding in vlan 10", other)
+	}
+
+	// test reset
+	b.Reset()
+	if b.Get(0, ip) != nil || b.Len() != 0 {
+		t.Errorf("got = %d; want 0", b.Len())
+	}
+}
+
+func TestBindingMapExpire(t *testing.T) {
+	var b BindingMap
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mac1 := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	mac2 := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})
+
+	// add old binding and binding with old and new claims
+	ip1 := layers.NewIPEndpoint(net.IP{192, 0, 2, 1})
+	ip2 := layers.NewIPEndpoint(net.IP{192, 0, 2, 2})
+	b.Add(0, ip1).SetTimestamp(timestamp)
+	b2 := b.Add(0, ip2)
+	b2.SetTimestamp(timestamp.Add(time.Minute))
+	b2.Claim(mac1).SetTimestamp(timestamp)
+	b2.Claim(mac2).SetTimestamp(timestamp.Add(time.Minute))
+
+	// expire
+	clock := &SourcesClock{}
+	clock.Update("", timestamp.Add(90*time.Second))
+	SetClock(clock)
+	defer SetClock(nil)
+	b.Expire(time.Minute)
+	if b.Get(0, ip1) != nil || b.Get(0, ip2) == nil {
+		t.Errorf("got = %v, %v; want nil, binding", b.Get(0, ip1),
+			b.Get(0, ip2))
+	}
+	if len(b2.Claims) != 1 || b2.Claims[mac2] == nil {
+		t.Errorf("got = %v; want claim of %s", b2.Claims, mac2)
+	}
+}
@@ -0,0 +1,64 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CDPInfo stores the CDP information advertised by a device
+type CDPInfo struct {
+	TimeInfo
+	DeviceID     string   `json:"device_id"`
+	Platform     string   `json:"platform"`
+	Version      string   `json:"software_version"`
+	PortID       string   `json:"port_id"`
+	NativeVLAN   uint16   `json:"native_vlan"`
+	VoiceVLAN    uint16   `json:"voice_vlan"`
+	Duplex       string   `json:"duplex"`
+	Capabilities []string `json:"capabilities"`
+	Addresses    []string `json:"addresses"`
+}
+
+// Print prints the CDP info to w
+func (c *CDPInfo) Print(w io.Writer) {
+	if c == nil {
+		return
+	}
+	cdpFmt := "    CDP: %-39t (age: %.f)\n"
+	fmt.Fprintf(w, cdpFmt, true, c.Age())
+
+	// print all fields that are set
+	valueFmt := "      %s: %s\n"
+	if c.DeviceID != "" {
+		fmt.Fprintf(w, valueFmt, "Device ID", c.DeviceID)
+	}
+	if c.Platform != "" {
+		fmt.Fprintf(w, valueFmt, "Platform", c.Platform)
+	}
+	if c.Version != "" {
+		// software versions often span multiple lines, only show
+		// the first one
+		version := strings.SplitN(c.Version, "\n", 2)[0]
+		fmt.Fprintf(w, valueFmt, "Software Version", version)
+	}
+	if c.PortID != "" {
+		fmt.Fprintf(w, valueFmt, "Port ID", c.PortID)
+	}
+	if c.NativeVLAN != 0 {
+		fmt.Fprintf(w, "      Native VLAN: %d\n", c.NativeVLAN)
+	}
+	if c.VoiceVLAN != 0 {
+		fmt.Fprintf(w, "      Voice VLAN: %d\n", c.VoiceVLAN)
+	}
+	if c.Duplex != "" {
+		fmt.Fprintf(w, valueFmt, "Duplex", c.Duplex)
+	}
+	if len(c.Capabilities) > 0 {
+		fmt.Fprintf(w, valueFmt, "Capabilities",
+			strings.Join(c.Capabilities, ", "))
+	}
+	for _, addr := range c.Addresses {
+		fmt.Fprintf(w, valueFmt, "Address", addr)
+	}
+}
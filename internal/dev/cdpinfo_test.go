@@ -0,0 +1,49 @@
+package dev
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCDPInfoPrint(t *testing.T) {
+	var c *CDPInfo
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	c.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	c = &CDPInfo{
+		DeviceID:     "switch1",
+		Platform:     "cisco WS-C2960-24TT-L",
+		Version:      "Cisco IOS Software\nCopyright (c) Cisco",
+		PortID:       "FastEthernet0/1",
+		NativeVLAN:   1,
+		VoiceVLAN:    10,
+		Duplex:       "full",
+		Capabilities: []string{"switch", "igmp"},
+		Addresses:    []string{"192.0.2.1"},
+	}
+	c.Print(&buf)
+	want = "    CDP: true                                    (age: -1)\n" +
+		"      Device ID: switch1\n" +
+		"      Platform: cisco WS-C2960-24TT-L\n" +
+		"      Software Version: Cisco IOS Software\n" +
+		"      Port ID: FastEthernet0/1\n" +
+		"      Native VLAN: 1\n" +
+		"      Voice VLAN: 10\n" +
+		"      Duplex: full\n" +
+		"      Capabilities: switch, igmp\n" +
+		"      Address: 192.0.2.1\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
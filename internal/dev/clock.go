@@ -0,0 +1,89 @@
+package dev
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// clock used for calculating ages of entries in the device table
+	clock Clock = WallClock{}
+)
+
+// Clock is a source of the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// WallClock is a clock that uses the current wall-clock time
+type WallClock struct{}
+
+// Now returns the current wall-clock time
+func (w WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// SourcesClock is a clock that advances with the timestamps of packets from
+// multiple sources read concurrently, e.g., multiple pcap files; its time is
+// the time of the active source that is furthest behind, so entries do not
+// age faster than the slowest source is read
+type SourcesClock struct {
+	sync.Mutex
+	sources map[string]time.Time
+	done    map[string]bool
+}
+
+// Update advances the clock of source to timestamp if it is newer than the
+// current time of source
+func (s *SourcesClock) Update(source string, timestamp time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	if s.sources == nil {
+		s.sources = make(map[string]time.Time)
+	}
+	if timestamp.After(s.sources[source]) {
+		s.sources[source] = timestamp
+	}
+}
+
+// Done marks source as finished, so it does not hold back the clock
+func (s *SourcesClock) Done(source string) {
+	s.Lock()
+	defer s.Unlock()
+	if s.done == nil {
+		s.done = make(map[string]bool)
+	}
+	s.done[source] = true
+}
+
+// Now returns the current time of the clock: the oldest time of all active
+// sources or, if all sources are done, the newest time of all sources
+func (s *SourcesClock) Now() time.Time {
+	s.Lock()
+	defer s.Unlock()
+	var oldest, newest time.Time
+	for source, t := range s.sources {
+		if t.After(newest) {
+			newest = t
+		}
+		if s.done[source] {
+			continue
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() {
+		return newest
+	}
+	return oldest
+}
+
+// SetClock sets the clock used for the device table, nil resets it to the
+// wall-clock
+func SetClock(c Clock) {
+	if c == nil {
+		c = WallClock{}
+	}
+	clock = c
+}
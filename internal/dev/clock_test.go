@@ -0,0 +1,67 @@
+package dev
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetClock(t *testing.T) {
+	var c SourcesClock
+	var i TimeInfo
+	var want, got float64
+
+	// set packet clock and reset it to wall-clock at the end
+	SetClock(&c)
+	defer SetClock(nil)
+
+	// test age relative to packet clock
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	i.SetTimestamp(timestamp)
+	c.Update("", timestamp.Add(10*time.Second))
+	want = 10
+	got = i.Age()
+	if got != want {
+		t.Errorf("got = %f; want %f", got, want)
+	}
+
+	// test expiry relative to packet clock
+	if i.IsExpired(time.Minute) {
+		t.Errorf("i.IsExpired() = true; want false")
+	}
+	c.Update("", timestamp.Add(2*time.Minute))
+	if !i.IsExpired(time.Minute) {
+		t.Errorf("i.IsExpired() = false; want true")
+	}
+}
+
+func TestSourcesClock(t *testing.T) {
+	var c SourcesClock
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// test default
+	if got := c.Now(); !got.IsZero() {
+		t.Errorf("got = %s; want zero time", got)
+	}
+
+	// test oldest time of active sources
+	c.Update("file1", timestamp.Add(time.Hour))
+	c.Update("file2", timestamp)
+	c.Update("file2", timestamp.Add(-time.Second))
+	if got := c.Now(); got != timestamp {
+		t.Errorf("got = %s; want %s", got, timestamp)
+	}
+
+	// test finished source does not hold back the clock
+	c.Done("file2")
+	if got := c.Now(); got != timestamp.Add(time.Hour) {
+		t.Errorf("got = %s; want %s", got, timestamp.Add(time.Hour))
+	}
+
+	// test newest time if all sources are done
+	c.Update("file3", timestamp.Add(time.Minute))
+	c.Done("file1")
+	c.Done("file3")
+	if got := c.Now(); got != timestamp.Add(time.Hour) {
+		t.Errorf("got = %s; want %s", got, timestamp.Add(time.Hour))
+	}
+}
@@ -0,0 +1,140 @@
+package dev
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	correlateMode bool
+)
+
+// SetCorrelate enables or disables correlation of devices with randomized
+// mac addresses to other devices
+func SetCorrelate(enable bool) {
+	correlateMode = enable
+}
+
+// correlationKeys returns the stable attributes of device d that are used
+// to correlate it to other devices: dhcp client ids, and hostnames only in
+// combination with the vendor class or fingerprint of a dhcp client, because
+// many devices use the same default hostnames
+func correlationKeys(d *DeviceInfo) []string {
+	var keys, hostnames, attrs []string
+	add := func(list *[]string, prefix, value string) {
+		if value != "" {
+			*list = append(*list, prefix+strings.ToLower(value))
+		}
+	}
+	for _, c := range []*DHCPClientInfo{d.DHCPv4Client, d.DHCPv6Client} {
+		if c == nil {
+			continue
+		}
+		add(&keys, c.Name+" client id:", c.ClientID)
+		add(&hostnames, "hostname:", c.Hostname)
+		add(&hostnames, "hostname:", strings.TrimSuffix(c.FQDN, "."))
+		add(&attrs, c.Name+" vendor class:", c.VendorClass)
+		add(&attrs, c.Name+" fingerprint:", c.Fingerprint)
+	}
+	if d.MDNS != nil {
+		for _, h := range d.MDNS.Hostnames {
+			h = strings.TrimSuffix(strings.TrimSuffix(h, "."),
+				".local")
+			add(&hostnames, "hostname:", h)
+		}
+	}
+	for _, h := range hostnames {
+		for _, a := range attrs {
+			keys = append(keys, h+","+a)
+		}
+	}
+	return keys
+}
+
+// UpdateCorrelation updates the correlated mac addresses of all devices if
+// correlation is enabled and the stable attributes of device changed
+func (d *DeviceMap) UpdateCorrelation(device *DeviceInfo) {
+	if !correlateMode ||
+		slices.Equal(correlationKeys(device), device.correlationKeys) {
+		return
+	}
+	d.correlate()
+}
+
+// updateCorrelation updates the correlated mac addresses of all devices if
+// correlation is enabled, e.g., after devices were removed
+func (d *DeviceMap) updateCorrelation() {
+	if correlateMode {
+		d.correlate()
+	}
+}
+
+// correlate sets the correlated mac addresses of all devices: devices that
+// share a stable attribute, directly or through other devices, belong to
+// the same group; only groups with a randomized mac address are reported
+func (d *DeviceMap) correlate() {
+	// union-find over all devices
+	parent := make(map[*DeviceInfo]*DeviceInfo)
+	var find func(*DeviceInfo) *DeviceInfo
+	find = func(device *DeviceInfo) *DeviceInfo {
+		if parent[device] != device {
+			parent[device] = find(parent[device])
+		}
+		return parent[device]
+	}
+	owners := make(map[string]*DeviceInfo)
+	for _, device := range d.m {
+		device.Correlated = nil
+		device.correlationKeys = correlationKeys(device)
+		if _, ok := parent[device]; !ok {
+			parent[device] = device
+		}
+		for _, key := range device.correlationKeys {
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = device
+				continue
+			}
+			parent[find(device)] = find(owner)
+		}
+	}
+
+	// collect groups
+	groups := make(map[*DeviceInfo][]*DeviceInfo)
+	for _, device := range d.m {
+		root := find(device)
+		groups[root] = append(groups[root], device)
+	}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		randomized := false
+		for _, device := range group {
+			if device.MACClass == MACClassRandomized {
+				randomized = true
+				break
+			}
+		}
+		if !randomized {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].MAC.LessThan(group[j].MAC)
+		})
+		for _, device := range group {
+			for _, other := range group {
+				// the same mac can be in the group multiple
+				// times if devices are keyed by vlan
+				n := len(device.Correlated)
+				if other.MAC == device.MAC || (n > 0 &&
+					device.Correlated[n-1] == other.MAC) {
+					continue
+				}
+				device.Correlated = append(device.Correlated,
+					other.MAC)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package dev
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestDeviceMapCorrelate(t *testing.T) {
+	var d DeviceMap
+	mac := func(s string) gopacket.Endpoint {
+		m, _ := net.ParseMAC(s)
+		return layers.NewMACEndpoint(m)
+	}
+	rand1 := mac("d2:a1:19:00:00:01")
+	rand2 := mac("d2:a1:19:00:00:02")
+	rand3 := mac("d2:a1:19:00:00:03")
+	global1 := mac("00:00:5e:00:53:01")
+	global2 := mac("00:00:5e:00:53:02")
+	global3 := mac("00:00:5e:00:53:03")
+	rand4 := mac("d2:a1:19:00:00:04")
+	rand5 := mac("d2:a1:19:00:00:05")
+	rand6 := mac("d2:a1:19:00:00:06")
+	rand7 := mac("d2:a1:19:00:00:07")
+
+	// randomized devices with same dhcp hostname and mdns hostname and
+	// same dhcp fingerprint
+	c := d.Add(rand1).AddDHCPv4Client()
+	c.Hostname = "Phone"
+	c.Fingerprint = "1,3,6,15"
+	d.Add(rand2).AddMDNS().AddHostname("phone.local.")
+	d.Get(rand2).AddDHCPv4Client().Fingerprint = "1,3,6,15"
+
+	// randomized devices with only the same default hostname are not
+	// correlated
+	d.Add(rand4).AddDHCPv4Client().Hostname = "iPhone"
+	d.Add(rand5).AddMDNS().AddHostname("iPhone.local.")
+
+	// randomized devices with same hostname but different fingerprints
+	// are not correlated
+	c = d.Add(rand6).AddDHCPv4Client()
+	c.Hostname = "android"
+	c.Fingerprint = "1,3,6"
+	c = d.Add(rand7).AddDHCPv4Client()
+	c.Hostname = "android"
+	c.Fingerprint = "1,3,6,15,119"
+
+	// randomized device with same dhcpv6 client id as global device
+	d.Add(rand3).AddDHCPv6Client().ClientID = "00:01:02"
+	d.Add(global1).AddDHCPv6Client().ClientID = "00:01:02"
+
+	// global devices with same hostname and vendor class are not
+	// reported
+	c = d.Add(global2).AddDHCPv4Client()
+	c.Hostname = "printer"
+	c.VendorClass = "printer-os"
+	c = d.Add(global3).AddDHCPv4Client()
+	c.Hostname = "printer"
+	c.VendorClass = "printer-os"
+
+	d.correlate()
+	tests := []struct {
+		mac  gopacket.Endpoint
+		want []gopacket.Endpoint
+	}{
+		{rand1, []gopacket.Endpoint{rand2}},
+		{rand2, []gopacket.Endpoint{rand1}},
+		{rand3, []gopacket.Endpoint{global1}},
+		{global1, []gopacket.Endpoint{rand3}},
+		{global2, nil},
+		{global3, nil},
+		{rand4, nil},
+		{rand5, nil},
+		{rand6, nil},
+		{rand7, nil},
+	}
+	for _, test := range tests {
+		got := d.Get(test.mac).Correlated
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got = %v; want %v", test.mac, got,
+				test.want)
+		}
+	}
+}
+
+func TestDeviceMapPrintCorrelate(t *testing.T) {
+	var d DeviceMap
+	var buf bytes.Buffer
+	var want, got string
+
+	// add randomized devices with same client id, correlation is
+	// updated when devices change, not when printing
+	SetCorrelate(true)
+	defer SetCorrelate(false)
+	m1, _ := net.ParseMAC("d2:a1:19:00:00:01")
+	m2, _ := net.ParseMAC("d2:a1:19:00:00:02")
+	d1 := d.Add(layers.NewMACEndpoint(m1))
+	d1.AddDHCPv4Client().ClientID = "01:02"
+	d2 := d.Add(layers.NewMACEndpoint(m2))
+	d2.AddDHCPv4Client().ClientID = "01:02"
+	d.Print(&buf)
+	if strings.Contains(buf.String(), "Correlated") {
+		t.Errorf("got = %s; want no correlated macs", buf.String())
+	}
+	d.UpdateCorrelation(d1)
+	d.UpdateCorrelation(d2)
+
+	// print with correlation
+	buf.Reset()
+	d.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 2                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: d2:a1:19:00:00:01 [randomized]              " +
+		"(age: -1, pkts: 0)\n" +
+		"  Correlated MACs: d2:a1:19:00:00:02\n" +
+		"  Properties:\n" +
+		"    DHCPv4 Client: true                          " +
+		"(age: -1)\n" +
+		"      Client ID: 01:02\n\n" +
+		"MAC: d2:a1:19:00:00:02 [randomized]              " +
+		"(age: -1, pkts: 0)\n" +
+		"  Correlated MACs: d2:a1:19:00:00:01\n" +
+		"  Properties:\n" +
+		"    DHCPv4 Client: true                          " +
+		"(age: -1)\n" +
+		"      Client ID: 01:02\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
+
+func TestDeviceMapDeleteCorrelate(t *testing.T) {
+	SetCorrelate(true)
+	defer SetCorrelate(false)
+
+	// correlate randomized devices with same client id
+	var d DeviceMap
+	m1, _ := net.ParseMAC("d2:a1:19:00:00:01")
+	m2, _ := net.ParseMAC("d2:a1:19:00:00:02")
+	d1 := d.Add(layers.NewMACEndpoint(m1))
+	d1.AddDHCPv4Client().ClientID = "01:02"
+	d.UpdateCorrelation(d1)
+	d2 := d.Add(layers.NewMACEndpoint(m2))
+	d2.AddDHCPv4Client().ClientID = "01:02"
+	d.UpdateCorrelation(d2)
+	if len(d1.Correlated) != 1 {
+		t.Fatalf("got = %v; want [%s]", d1.Correlated, m2)
+	}
+
+	// deleting a device updates the correlation
+	d.Delete(d2)
+	if d1.Correlated != nil {
+		t.Errorf("got = %v; want []", d1.Correlated)
+	}
+}
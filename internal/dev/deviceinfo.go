@@ -1,36 +1,320 @@
 package dev
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
 )
 
 // DeviceInfo is a device found on the network
 type DeviceInfo struct {
 	TimeInfo
-	MAC       gopacket.Endpoint
-	VLANs     VNetMap
-	VXLANs    VNetMap
-	GENEVEs   VNetMap
-	Powerline PropInfo
-	Bridge    PropInfo
-	DHCP      PropInfo
-	Router    PropInfo
-	Prefixes  PrefixList
-	Packets   int
-	UCasts    AddrMap
-	MCasts    AddrMap
-	MACPeers  AddrMap
-	IPPeers   AddrMap
+	VLAN         uint32
+	MAC          gopacket.Endpoint
+	Vendor       string
+	MACClass     string
+	Correlated   []gopacket.Endpoint
+	Interfaces   IfaceMap
+	VLANs        VNetMap
+	VXLANs       VNetMap
+	GENEVEs      VNetMap
+	Powerline    PropInfo
+	Bridge       PropInfo
+	DHCP         PropInfo
+	Router       PropInfo
+	RA           *RAInfo
+	Prefixes     PrefixList
+	LLDP         *LLDPInfo
+	CDP          *CDPInfo
+	MDNS         *MDNSInfo
+	DHCPv4Client *DHCPClientInfo
+	DHCPv6Client *DHCPClientInfo
+	DHCPv4Server *DHCPServerInfo
+	DHCPv6Server *DHCPServerInfo
+	Packets      int
+	UCasts       AddrMap
+	Tentative    AddrMap
+	MCasts       AddrMap
+	MACPeers     AddrMap
+	IPPeers      AddrMap
+	events       *eventEmitter
+
+	// correlationKeys are the stable attributes of the device used in
+	// the last correlation
+	correlationKeys []string
+}
+
+// newDeviceInfo creates a new device with the mac address linkAddr
+func newDeviceInfo(linkAddr gopacket.Endpoint) *DeviceInfo {
+	device := DeviceInfo{}
+	device.MAC = linkAddr
+	device.Vendor = ouiDB.Lookup(linkAddr)
+	device.MACClass = ClassifyMAC(linkAddr)
+	device.Powerline.Name = "Powerline"
+	device.Bridge.Name = "Bridge"
+	device.DHCP.Name = "DHCP Server"
+	device.Router.Name = "Router"
+	device.UCasts.Name = "Unicast Addresses"
+	device.Tentative.Name = "Tentative Addresses"
+	device.MCasts.Name = "Multicast Addresses"
+	device.MACPeers.Name = "MAC Peers"
+	device.IPPeers.Name = "IP Peers"
+	return &device
+}
+
+// device property names
+const (
+	PropertyBridge       = "bridge"
+	PropertyDHCPServer   = "dhcp_server"
+	PropertyRouter       = "router"
+	PropertyRA           = "router_advertisement"
+	PropertyPowerline    = "powerline"
+	PropertyLLDP         = "lldp"
+	PropertyCDP          = "cdp"
+	PropertyMDNS         = "mdns"
+	PropertyDHCPv4Client = "dhcpv4_client"
+	PropertyDHCPv6Client = "dhcpv6_client"
+	PropertyDHCPv4Server = "dhcpv4_server"
+	PropertyDHCPv6Server = "dhcpv6_server"
+)
+
+// Properties are the names of all device properties
+var Properties = []string{
+	PropertyBridge,
+	PropertyDHCPServer,
+	PropertyRouter,
+	PropertyRA,
+	PropertyPowerline,
+	PropertyLLDP,
+	PropertyCDP,
+	PropertyMDNS,
+	PropertyDHCPv4Client,
+	PropertyDHCPv6Client,
+	PropertyDHCPv4Server,
+	PropertyDHCPv6Server,
+}
+
+// IsValidProperty checks if name is a device property name
+func IsValidProperty(name string) bool {
+	for _, p := range Properties {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasProperty checks if the device has the property with name
+func (d *DeviceInfo) HasProperty(name string) bool {
+	switch name {
+	case PropertyBridge:
+		return d.Bridge.IsEnabled()
+	case PropertyDHCPServer:
+		return d.DHCP.IsEnabled()
+	case PropertyRouter:
+		return d.Router.IsEnabled()
+	case PropertyRA:
+		return d.RA != nil
+	case PropertyPowerline:
+		return d.Powerline.IsEnabled()
+	case PropertyLLDP:
+		return d.LLDP != nil
+	case PropertyCDP:
+		return d.CDP != nil
+	case PropertyMDNS:
+		return d.MDNS != nil
+	case PropertyDHCPv4Client:
+		return d.DHCPv4Client != nil
+	case PropertyDHCPv6Client:
+		return d.DHCPv6Client != nil
+	case PropertyDHCPv4Server:
+		return d.DHCPv4Server != nil
+	case PropertyDHCPv6Server:
+		return d.DHCPv6Server != nil
+	}
+	return false
+}
+
+// InVLAN checks if the device was seen in vlan; vlan 0 matches untagged
+// devices
+func (d *DeviceInfo) InVLAN(vlan uint32) bool {
+	if vlanMode {
+		return d.VLAN == vlan
+	}
+	if vlan == 0 {
+		return d.VLANs.Len() == 0
+	}
+	return d.VLANs.Get(vlan) != nil
+}
+
+// AddRA returns the router advertisement info of the device, it is created
+// if necessary
+func (d *DeviceInfo) AddRA() *RAInfo {
+	if d.RA == nil {
+		debug("Adding new RA entry")
+		d.RA = &RAInfo{}
+	}
+	return d.RA
+}
+
+// AddLLDP returns the LLDP info of the device, it is created if necessary
+func (d *DeviceInfo) AddLLDP() *LLDPInfo {
+	if d.LLDP == nil {
+		debug("Adding new LLDP entry")
+		d.LLDP = &LLDPInfo{}
+	}
+	return d.LLDP
+}
+
+// AddCDP returns the CDP info of the device, it is created if necessary
+func (d *DeviceInfo) AddCDP() *CDPInfo {
+	if d.CDP == nil {
+		debug("Adding new CDP entry")
+		d.CDP = &CDPInfo{}
+	}
+	return d.CDP
+}
+
+// AddMDNS returns the mDNS info of the device, it is created if necessary
+func (d *DeviceInfo) AddMDNS() *MDNSInfo {
+	if d.MDNS == nil {
+		debug("Adding new mDNS entry")
+		d.MDNS = &MDNSInfo{}
+	}
+	return d.MDNS
+}
+
+// AddDHCPv4Client returns the DHCPv4 client info of the device, it is
+// created if necessary
+func (d *DeviceInfo) AddDHCPv4Client() *DHCPClientInfo {
+	if d.DHCPv4Client == nil {
+		debug("Adding new DHCPv4 client entry")
+		d.DHCPv4Client = &DHCPClientInfo{Name: "DHCPv4 Client"}
+	}
+	return d.DHCPv4Client
+}
+
+// AddDHCPv6Client returns the DHCPv6 client info of the device, it is
+// created if necessary
+func (d *DeviceInfo) AddDHCPv6Client() *DHCPClientInfo {
+	if d.DHCPv6Client == nil {
+		debug("Adding new DHCPv6 client entry")
+		d.DHCPv6Client = &DHCPClientInfo{Name: "DHCPv6 Client"}
+	}
+	return d.DHCPv6Client
+}
+
+// AddDHCPv4Server returns the DHCPv4 server info of the device, it is
+// created if necessary
+func (d *DeviceInfo) AddDHCPv4Server() *DHCPServerInfo {
+	if d.DHCPv4Server == nil {
+		debug("Adding new DHCPv4 server entry")
+		d.DHCPv4Server = &DHCPServerInfo{Name: "DHCPv4 Server"}
+	}
+	return d.DHCPv4Server
+}
+
+// AddDHCPv6Server returns the DHCPv6 server info of the device, it is
+// created if necessary
+func (d *DeviceInfo) AddDHCPv6Server() *DHCPServerInfo {
+	if d.DHCPv6Server == nil {
+		debug("Adding new DHCPv6 server entry")
+		d.DHCPv6Server = &DHCPServerInfo{Name: "DHCPv6 Server"}
+	}
+	return d.DHCPv6Server
+}
+
+// Expire removes all device information not seen within timeout
+func (d *DeviceInfo) Expire(timeout time.Duration) {
+	// expire interfaces
+	d.Interfaces.Expire(timeout)
+
+	// expire vnets
+	d.VLANs.Expire(timeout)
+	d.VXLANs.Expire(timeout)
+	d.GENEVEs.Expire(timeout)
+
+	// expire properties
+	d.Powerline.Expire(timeout)
+	d.Bridge.Expire(timeout)
+	d.DHCP.Expire(timeout)
+	d.Router.Expire(timeout)
+	if d.RA != nil && d.RA.IsExpired(timeout) {
+		debug("Expiring RA entry")
+		d.RA = nil
+	}
+	d.Prefixes.Expire(timeout)
+	if d.LLDP != nil && d.LLDP.IsExpired(timeout) {
+		debug("Expiring LLDP entry")
+		d.LLDP = nil
+	}
+	if d.CDP != nil && d.CDP.IsExpired(timeout) {
+		debug("Expiring CDP entry")
+		d.CDP = nil
+	}
+	if d.MDNS != nil && d.MDNS.IsExpired(timeout) {
+		debug("Expiring mDNS entry")
+		d.MDNS = nil
+	}
+	if d.MDNS != nil {
+		d.MDNS.Expire(timeout)
+	}
+	if d.DHCPv4Client != nil && d.DHCPv4Client.IsExpired(timeout) {
+		debug("Expiring DHCPv4 client entry")
+		d.DHCPv4Client = nil
+	}
+	if d.DHCPv6Client != nil && d.DHCPv6Client.IsExpired(timeout) {
+		debug("Expiring DHCPv6 client entry")
+		d.DHCPv6Client = nil
+	}
+	if d.DHCPv4Server != nil && d.DHCPv4Server.IsExpired(timeout) {
+		debug("Expiring DHCPv4 server entry")
+		d.DHCPv4Server = nil
+	}
+	if d.DHCPv6Server != nil && d.DHCPv6Server.IsExpired(timeout) {
+		debug("Expiring DHCPv6 server entry")
+		d.DHCPv6Server = nil
+	}
+
+	// expire addresses
+	d.UCasts.Expire(timeout)
+	d.Tentative.Expire(timeout)
+	d.MCasts.Expire(timeout)
+	d.MACPeers.Expire(timeout)
+	d.IPPeers.Expire(timeout)
 }
 
 // Print prints the device to w
 func (d *DeviceInfo) Print(w io.Writer) {
 	// print MAC address
 	macFmt := "MAC: %-43s (age: %.f, pkts: %d)\n"
-	fmt.Fprintf(w, macFmt, d.MAC, d.Age(), d.Packets)
+	mac := d.MAC.String()
+	if d.Vendor != "" {
+		mac += " (" + d.Vendor + ")"
+	}
+	if d.MACClass != "" && d.MACClass != MACClassGlobal {
+		mac += " [" + d.MACClass + "]"
+	}
+	fmt.Fprintf(w, macFmt, mac, d.Age(), d.Packets)
+
+	// print correlated mac addresses
+	if len(d.Correlated) > 0 {
+		macs := make([]string, len(d.Correlated))
+		for i, m := range d.Correlated {
+			macs[i] = m.String()
+		}
+		fmt.Fprintf(w, "  Correlated MACs: %s\n",
+			strings.Join(macs, ", "))
+	}
+
+	// print interfaces
+	d.Interfaces.Print(w)
 
 	// print properties
 	propsHeader := "  Properties:\n"
@@ -38,6 +322,14 @@ func (d *DeviceInfo) Print(w io.Writer) {
 		d.DHCP.IsEnabled() ||
 		d.Router.IsEnabled() ||
 		d.Powerline.IsEnabled() ||
+		d.RA != nil ||
+		d.LLDP != nil ||
+		d.CDP != nil ||
+		d.MDNS != nil ||
+		d.DHCPv4Client != nil ||
+		d.DHCPv6Client != nil ||
+		d.DHCPv4Server != nil ||
+		d.DHCPv6Server != nil ||
 		d.VLANs.Len() > 0 ||
 		d.VXLANs.Len() > 0 ||
 		d.GENEVEs.Len() > 0 {
@@ -48,9 +340,17 @@ func (d *DeviceInfo) Print(w io.Writer) {
 		// print device properties
 		d.Bridge.Print(w)
 		d.DHCP.Print(w)
+		d.DHCPv4Server.Print(w)
+		d.DHCPv6Server.Print(w)
+		d.DHCPv4Client.Print(w)
+		d.DHCPv6Client.Print(w)
 		d.Router.Print(w)
+		d.RA.Print(w)
 		d.Prefixes.Print(w)
 		d.Powerline.Print(w)
+		d.LLDP.Print(w)
+		d.CDP.Print(w)
+		d.MDNS.Print(w)
 		d.VLANs.Print(w)
 		d.VXLANs.Print(w)
 		d.GENEVEs.Print(w)
@@ -58,7 +358,190 @@ func (d *DeviceInfo) Print(w io.Writer) {
 
 	// print addresses
 	d.UCasts.Print(w)
+	d.Tentative.Print(w)
 	d.MCasts.Print(w)
 	d.MACPeers.Print(w)
 	d.IPPeers.Print(w)
 }
+
+// MarshalJSON converts the device to json
+func (d *DeviceInfo) MarshalJSON() ([]byte, error) {
+	type properties struct {
+		Bridge       *PropInfo       `json:"bridge"`
+		DHCP         *PropInfo       `json:"dhcp_server"`
+		Router       *PropInfo       `json:"router"`
+		RA           *RAInfo         `json:"router_advertisement"`
+		Powerline    *PropInfo       `json:"powerline"`
+		LLDP         *LLDPInfo       `json:"lldp"`
+		CDP          *CDPInfo        `json:"cdp"`
+		MDNS         *MDNSInfo       `json:"mdns"`
+		DHCPv4Client *DHCPClientInfo `json:"dhcpv4_client"`
+		DHCPv6Client *DHCPClientInfo `json:"dhcpv6_client"`
+		DHCPv4Server *DHCPServerInfo `json:"dhcpv4_server"`
+		DHCPv6Server *DHCPServerInfo `json:"dhcpv6_server"`
+	}
+	var correlated []string
+	for _, mac := range d.Correlated {
+		correlated = append(correlated, mac.String())
+	}
+	var vlan *uint32
+	if vlanMode {
+		vlan = &d.VLAN
+	}
+	return json.Marshal(struct {
+		VLAN       *uint32  `json:"vlan,omitempty"`
+		MAC        string   `json:"mac"`
+		Vendor     string   `json:"vendor"`
+		MACClass   string   `json:"mac_class"`
+		Correlated []string `json:"correlated_macs"`
+		TimeInfo
+		Packets    int         `json:"packets"`
+		Interfaces *IfaceMap   `json:"interfaces"`
+		Properties properties  `json:"properties"`
+		Prefixes   *PrefixList `json:"prefixes"`
+		VLANs      *VNetMap    `json:"vlans"`
+		VXLANs     *VNetMap    `json:"vxlans"`
+		GENEVEs    *VNetMap    `json:"geneves"`
+		UCasts     *AddrMap    `json:"unicast_addresses"`
+		Tentative  *AddrMap    `json:"tentative_addresses"`
+		MCasts     *AddrMap    `json:"multicast_addresses"`
+		MACPeers   *AddrMap    `json:"mac_peers"`
+		IPPeers    *AddrMap    `json:"ip_peers"`
+	}{
+		VLAN:       vlan,
+		MAC:        d.MAC.String(),
+		Vendor:     d.Vendor,
+		MACClass:   d.MACClass,
+		Correlated: correlated,
+		TimeInfo:   d.TimeInfo,
+		Packets:    d.Packets,
+		Interfaces: &d.Interfaces,
+		Properties: properties{
+			Bridge:       &d.Bridge,
+			DHCP:         &d.DHCP,
+			Router:       &d.Router,
+			RA:           d.RA,
+			Powerline:    &d.Powerline,
+			LLDP:         d.LLDP,
+			CDP:          d.CDP,
+			MDNS:         d.MDNS,
+			DHCPv4Client: d.DHCPv4Client,
+			DHCPv6Client: d.DHCPv6Client,
+			DHCPv4Server: d.DHCPv4Server,
+			DHCPv6Server: d.DHCPv6Server,
+		},
+		Prefixes:  &d.Prefixes,
+		VLANs:     &d.VLANs,
+		VXLANs:    &d.VXLANs,
+		GENEVEs:   &d.GENEVEs,
+		UCasts:    &d.UCasts,
+		Tentative: &d.Tentative,
+		MCasts:    &d.MCasts,
+		MACPeers:  &d.MACPeers,
+		IPPeers:   &d.IPPeers,
+	})
+}
+
+// UnmarshalJSON restores the device from json; vendor, mac class and
+// correlated macs are derived from the mac address again
+func (d *DeviceInfo) UnmarshalJSON(data []byte) error {
+	var device struct {
+		VLAN uint32 `json:"vlan"`
+		MAC  string `json:"mac"`
+		TimeInfo
+		Packets    int       `json:"packets"`
+		Interfaces *IfaceMap `json:"interfaces"`
+		Properties struct {
+			Bridge       *PropInfo       `json:"bridge"`
+			DHCP         *PropInfo       `json:"dhcp_server"`
+			Router       *PropInfo       `json:"router"`
+			RA           *RAInfo         `json:"router_advertisement"`
+			Powerline    *PropInfo       `json:"powerline"`
+			LLDP         *LLDPInfo       `json:"lldp"`
+			CDP          *CDPInfo        `json:"cdp"`
+			MDNS         *MDNSInfo       `json:"mdns"`
+			DHCPv4Client *DHCPClientInfo `json:"dhcpv4_client"`
+			DHCPv6Client *DHCPClientInfo `json:"dhcpv6_client"`
+			DHCPv4Server *DHCPServerInfo `json:"dhcpv4_server"`
+			DHCPv6Server *DHCPServerInfo `json:"dhcpv6_server"`
+		} `json:"properties"`
+		Prefixes  *PrefixList `json:"prefixes"`
+		VLANs     *VNetMap    `json:"vlans"`
+		VXLANs    *VNetMap    `json:"vxlans"`
+		GENEVEs   *VNetMap    `json:"geneves"`
+		UCasts    *AddrMap    `json:"unicast_addresses"`
+		Tentative *AddrMap    `json:"tentative_addresses"`
+		MCasts    *AddrMap    `json:"multicast_addresses"`
+		MACPeers  *AddrMap    `json:"mac_peers"`
+		IPPeers   *AddrMap    `json:"ip_peers"`
+	}
+	if err := json.Unmarshal(data, &device); err != nil {
+		return err
+	}
+	mac, err := net.ParseMAC(device.MAC)
+	if err != nil {
+		return err
+	}
+
+	// create device and restore its properties and addresses
+	*d = *newDeviceInfo(layers.NewMACEndpoint(mac))
+	d.TimeInfo = device.TimeInfo
+	d.VLAN = device.VLAN
+	d.Packets = device.Packets
+	if device.Interfaces != nil {
+		d.Interfaces = *device.Interfaces
+	}
+	props := device.Properties
+	for _, prop := range []struct {
+		dst *PropInfo
+		src *PropInfo
+	}{
+		{&d.Bridge, props.Bridge},
+		{&d.DHCP, props.DHCP},
+		{&d.Router, props.Router},
+		{&d.Powerline, props.Powerline},
+	} {
+		if prop.src != nil {
+			prop.dst.TimeInfo = prop.src.TimeInfo
+			prop.dst.Enabled = prop.src.Enabled
+		}
+	}
+	d.RA = props.RA
+	d.LLDP = props.LLDP
+	d.CDP = props.CDP
+	d.MDNS = props.MDNS
+	d.DHCPv4Client = props.DHCPv4Client
+	d.DHCPv6Client = props.DHCPv6Client
+	d.DHCPv4Server = props.DHCPv4Server
+	d.DHCPv6Server = props.DHCPv6Server
+	if device.Prefixes != nil {
+		d.Prefixes = *device.Prefixes
+	}
+	for _, vnets := range []struct {
+		dst *VNetMap
+		src *VNetMap
+	}{
+		{&d.VLANs, device.VLANs},
+		{&d.VXLANs, device.VXLANs},
+		{&d.GENEVEs, device.GENEVEs},
+	} {
+		if vnets.src != nil {
+			vnets.dst.m = vnets.src.m
+		}
+	}
+	for _, addrs := range []struct {
+		dst *AddrMap
+		src *AddrMap
+	}{
+		{&d.UCasts, device.UCasts},
+		{&d.Tentative, device.Tentative},
+		{&d.MCasts, device.MCasts},
+		{&d.MACPeers, device.MACPeers},
+		{&d.IPPeers, device.IPPeers},
+	} {
+		if addrs.src != nil {
+			addrs.dst.m = addrs.src.m
+		}
+	}
+	return nil
+}
@@ -34,18 +34,15 @@ func TestDeviceInfo(t *testing.T) {
 
 	// set vnets
 	// vlan
-	vlan := d.VLANs.Add(42)
-	vlan.Type = "VLAN"
+	vlan := d.VLANs.Add(42, "VLAN")
 	vlan.ID = 42
 	vlan.Packets = 1
 	// vxlan
-	vxlan := d.VXLANs.Add(43)
-	vxlan.Type = "VXLAN"
+	vxlan := d.VXLANs.Add(43, "VXLAN")
 	vxlan.ID = 43
 	vxlan.Packets = 2
 	// geneve
-	geneve := d.GENEVEs.Add(44)
-	geneve.Type = "GENEVE"
+	geneve := d.GENEVEs.Add(44, "GENEVE")
 	geneve.ID = 44
 	geneve.Packets = 3
 
@@ -94,6 +91,10 @@ func TestDeviceInfo(t *testing.T) {
 		"(age: -1)\n" +
 		"      Prefix: 2001:db8:0:1::/64                  " +
 		"(age: -1)\n" +
+		"        Valid Lifetime: 2592000\n" +
+		"        Preferred Lifetime: 604800\n" +
+		"        On-Link: true\n" +
+		"        Autonomous: true\n" +
 		"    Powerline: true                              " +
 		"(age: -1)\n" +
 		"    VLAN: 42                                     " +
@@ -1,10 +1,12 @@
 package dev
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/gopacket/gopacket"
 )
@@ -12,32 +14,89 @@ import (
 // DeviceMap is the device table definition
 type DeviceMap struct {
 	sync.Mutex
-	Packets int
-	m       map[gopacket.Endpoint]*DeviceInfo
+	Packets   int
+	Alerts    AlertList
+	Bindings  BindingMap
+	DADProbes BindingMap
+	Events    EventBus
+	m         map[deviceKey]*DeviceInfo
+	vlan      uint32
+
+	// packetTime is the timestamp of the packet that is currently parsed
+	packetTime time.Time
+
+	// embedded maps mac addresses to the addresses of other devices that
+	// embed them
+	embedded map[deviceKey][]embeddedAddr
+}
+
+// SetVLAN sets the vlan of the packet that is currently parsed; if devices
+// are keyed by vlan, Add and Get use it to identify devices
+func (d *DeviceMap) SetVLAN(vlan uint32) {
+	d.vlan = vlan
+}
+
+// SetPacketTime sets the timestamp of the packet that is currently parsed;
+// it is used for alerts raised by the device table itself
+func (d *DeviceMap) SetPacketTime(timestamp time.Time) {
+	d.packetTime = timestamp
+}
+
+// key returns the key of the device with linkAddr in the device table
+func (d *DeviceMap) key(linkAddr gopacket.Endpoint) deviceKey {
+	if !vlanMode {
+		return deviceKey{mac: linkAddr}
+	}
+	return deviceKey{vlan: d.vlan, mac: linkAddr}
 }
 
 // Add adds a device to the device table and returns the new device info entry
 func (d *DeviceMap) Add(linkAddr gopacket.Endpoint) *DeviceInfo {
 	// create map if necessary
 	if d.m == nil {
-		d.m = make(map[gopacket.Endpoint]*DeviceInfo)
+		d.m = make(map[deviceKey]*DeviceInfo)
 	}
 	// create table entries if necessary
-	if d.m[linkAddr] == nil {
+	key := d.key(linkAddr)
+	if d.m[key] == nil {
 		debug("Adding new entry")
-		device := DeviceInfo{}
-		device.MAC = linkAddr
-		device.Powerline.Name = "Powerline"
-		device.Bridge.Name = "Bridge"
-		device.DHCP.Name = "DHCP Server"
-		device.Router.Name = "Router"
-		device.UCasts.Name = "Unicast Addresses"
-		device.MCasts.Name = "Multicast Addresses"
-		device.MACPeers.Name = "MAC Peers"
-		device.IPPeers.Name = "IP Peers"
-		d.m[linkAddr] = &device
+		device := newDeviceInfo(linkAddr)
+		device.VLAN = key.vlan
+		d.m[key] = device
+		d.attach(device)
+		device.events.emit(EventDeviceAdded, "")
+		d.checkEmbeddedMACs(key)
+	}
+	return d.m[key]
+}
+
+// attach attaches the device to the event bus of the device table
+func (d *DeviceMap) attach(device *DeviceInfo) {
+	events := &eventEmitter{devices: d, device: device}
+	device.events = events
+	device.UCasts.setEvents(events, EventAddressAdded,
+		EventAddressRemoved)
+	device.UCasts.added = func(a *AddrInfo) {
+		device.classifyAddr(a)
+		d.addEmbeddedMAC(device, a)
+	}
+	device.MCasts.setEvents(events, EventMulticastJoin,
+		EventMulticastLeave)
+	device.VLANs.events = events
+	device.VXLANs.events = events
+	device.GENEVEs.events = events
+	device.Powerline.events = events
+	device.Bridge.events = events
+	device.DHCP.events = events
+	device.Router.events = events
+}
+
+// UpdateVendors looks up the vendors of all devices again, e.g., after
+// loading a new oui database
+func (d *DeviceMap) UpdateVendors() {
+	for _, device := range d.m {
+		device.Vendor = ouiDB.Lookup(device.MAC)
 	}
-	return d.m[linkAddr]
 }
 
 // Get returns device information for device with linkAddr
@@ -45,38 +104,178 @@ func (d *DeviceMap) Get(linkAddr gopacket.Endpoint) *DeviceInfo {
 	if d == nil {
 		return nil
 	}
-	if d.m[linkAddr] == nil {
-		return nil
+	return d.m[d.key(linkAddr)]
+}
+
+// Delete removes the device from the device table
+func (d *DeviceMap) Delete(device *DeviceInfo) {
+	// events are not caused by a packet in a vlan
+	d.vlan = 0
+	key := deviceKey{device.VLAN, device.MAC}
+	if d.m[key] == device {
+		delete(d.m, key)
+		device.events.emit(EventDeviceRemoved, "")
 	}
-	return d.m[linkAddr]
+	d.updateCorrelation()
 }
 
 // Reset deletes all device information entries
 func (d *DeviceMap) Reset() {
+	// events are not caused by a packet in a vlan
+	d.vlan = 0
+	for _, device := range d.m {
+		device.events.emit(EventDeviceRemoved, "")
+	}
 	d.m = nil
+	d.Alerts.Reset()
+	d.Bindings.Reset()
+	d.DADProbes.Reset()
+	d.embedded = nil
+}
+
+// Expire removes all devices and device information not seen within timeout
+func (d *DeviceMap) Expire(timeout time.Duration) {
+	// events are not caused by a packet in a vlan
+	d.vlan = 0
+	for key, device := range d.m {
+		if device.IsExpired(timeout) {
+			debug("Expiring entry")
+			delete(d.m, key)
+			device.events.emit(EventDeviceRemoved, "")
+			continue
+		}
+		device.Expire(timeout)
+	}
+	d.Alerts.Expire(timeout)
+	d.Bindings.Expire(timeout)
+	d.DADProbes.Expire(timeout)
+	d.expireEmbeddedMACs()
+	d.updateCorrelation()
+}
+
+// sorted returns all devices matching filter f sorted by f, or by mac
+// address if f is nil; if devices are keyed by vlan, they are sorted by vlan
+// first
+func (d *DeviceMap) sorted(f *Filter) []*DeviceInfo {
+	devices := make([]*DeviceInfo, 0, len(d.m))
+	for _, device := range d.m {
+		if f.Match(device) {
+			devices = append(devices, device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].VLAN != devices[j].VLAN {
+			return devices[i].VLAN < devices[j].VLAN
+		}
+		return f.Less(devices[i], devices[j])
+	})
+	return devices
+}
+
+// Devices returns all devices matching filter f sorted by f
+func (d *DeviceMap) Devices(f *Filter) []*DeviceInfo {
+	return d.sorted(f)
 }
 
 // Print prints all devices to w
 func (d *DeviceMap) Print(w io.Writer) {
+	d.PrintFilter(w, nil)
+}
+
+// PrintFilter prints all devices matching filter f to w; if devices are
+// keyed by vlan, they are grouped by vlan
+func (d *DeviceMap) PrintFilter(w io.Writer, f *Filter) {
+	devices := d.sorted(f)
 	devicesFmt := "===================================" +
 		"===================================\n" +
 		"Devices: %-39d (pkts: %d)\n" +
 		"===================================" +
 		"===================================\n"
-	fmt.Fprintf(w, devicesFmt, len(d.m), d.Packets)
-
-	// sort devices by mac address
-	var macs []gopacket.Endpoint
-	for i := range d.m {
-		macs = append(macs, i)
-	}
-	sort.Slice(macs, func(i, j int) bool {
-		return macs[i].LessThan(macs[j])
-	})
+	fmt.Fprintf(w, devicesFmt, len(devices), d.Packets)
 
 	// print sorted devices
-	for _, mac := range macs {
-		d.m[mac].Print(w)
+	var segments []*Segment
+	if vlanMode {
+		segments = getSegments(devices)
+	}
+	for _, device := range devices {
+		if len(segments) > 0 && segments[0].VLAN == device.VLAN {
+			segments[0].Print(w)
+			segments = segments[1:]
+		}
+		device.Print(w)
 		fmt.Fprintln(w)
 	}
+
+	// print alerts
+	d.Alerts.Print(w)
+}
+
+// table returns the device table with all devices matching filter f for
+// conversion to json
+func (d *DeviceMap) table(f *Filter) any {
+	devices := d.sorted(f)
+	var segments []*Segment
+	if vlanMode {
+		segments = getSegments(devices)
+		if segments == nil {
+			segments = []*Segment{}
+		}
+	}
+	return struct {
+		Packets  int           `json:"packets"`
+		Devices  []*DeviceInfo `json:"devices"`
+		Segments []*Segment    `json:"vlans,omitempty"`
+		Alerts   *AlertList    `json:"alerts"`
+	}{
+		Packets:  d.Packets,
+		Devices:  devices,
+		Segments: segments,
+		Alerts:   &d.Alerts,
+	}
+}
+
+// MarshalJSON converts the device table to json
+func (d *DeviceMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.table(nil))
+}
+
+// PrintJSON prints all devices as json to w
+func (d *DeviceMap) PrintJSON(w io.Writer) error {
+	return d.PrintJSONFilter(w, nil)
+}
+
+// PrintJSONFilter prints all devices matching filter f as json to w
+func (d *DeviceMap) PrintJSONFilter(w io.Writer, f *Filter) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.table(f))
+}
+
+// UnmarshalJSON restores the device table from json; address bindings and
+// dad probes are not part of the json output and are reset
+func (d *DeviceMap) UnmarshalJSON(data []byte) error {
+	var table struct {
+		Packets int           `json:"packets"`
+		Devices []*DeviceInfo `json:"devices"`
+		Alerts  *AlertList    `json:"alerts"`
+	}
+	table.Alerts = &d.Alerts
+	if err := json.Unmarshal(data, &table); err != nil {
+		return err
+	}
+	d.Packets = table.Packets
+	d.m = make(map[deviceKey]*DeviceInfo)
+	for _, device := range table.Devices {
+		if !vlanMode {
+			device.VLAN = 0
+		}
+		d.m[deviceKey{device.VLAN, device.MAC}] = device
+		d.attach(device)
+	}
+	d.Bindings.Reset()
+	d.DADProbes.Reset()
+	d.indexEmbeddedMACs()
+	d.updateCorrelation()
+	return nil
 }
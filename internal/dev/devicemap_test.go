@@ -5,6 +5,7 @@ import (
 	"log"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/gopacket/gopacket/layers"
 )
@@ -126,3 +127,224 @@ func TestDeviceMapPrint(t *testing.T) {
 		t.Errorf("got = %s; want = %s", got, want)
 	}
 }
+
+func TestDeviceMapPrintJSON(t *testing.T) {
+	var d DeviceMap
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	d.PrintJSON(&buf)
+	want = "{\n" +
+		"  \"packets\": 0,\n" +
+		"  \"devices\": [],\n" +
+		"  \"alerts\": []\n" +
+		"}\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	// prepare mac and ip
+	m, err := net.ParseMAC("00:00:5e:00:53:01")
+	if err != nil {
+		log.Fatal(err)
+	}
+	mac := layers.NewMACEndpoint(m)
+	ip := layers.NewIPEndpoint(net.ParseIP("192.0.2.1"))
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// add and test
+	d.Packets = 1
+	device := d.Add(mac)
+	device.Packets = 1
+	device.SetTimestamp(timestamp)
+	device.Router.Enable()
+	device.Router.SetTimestamp(timestamp)
+	vlan := device.VLANs.Add(42, "VLAN")
+	vlan.Packets = 1
+	device.UCasts.Add(ip).Packets = 1
+	d.PrintJSON(&buf)
+	want = `{
+  "packets": 1,
+  "devices": [
+    {
+      "mac": "00:00:5e:00:53:01",
+      "vendor": "",
+      "mac_class": "globally unique",
+      "correlated_macs": null,
+      "first_seen": "2020-01-02T03:04:05Z",
+      "last_seen": "2020-01-02T03:04:05Z",
+      "packets": 1,
+      "interfaces": [],
+      "properties": {
+        "bridge": {
+          "first_seen": "0001-01-01T00:00:00Z",
+          "last_seen": "0001-01-01T00:00:00Z",
+          "name": "Bridge",
+          "enabled": false
+        },
+        "dhcp_server": {
+          "first_seen": "0001-01-01T00:00:00Z",
+          "last_seen": "0001-01-01T00:00:00Z",
+          "name": "DHCP Server",
+          "enabled": false
+        },
+        "router": {
+          "first_seen": "2020-01-02T03:04:05Z",
+          "last_seen": "2020-01-02T03:04:05Z",
+          "name": "Router",
+          "enabled": true
+        },
+        "router_advertisement": null,
+        "powerline": {
+          "first_seen": "0001-01-01T00:00:00Z",
+          "last_seen": "0001-01-01T00:00:00Z",
+          "name": "Powerline",
+          "enabled": false
+        },
+        "lldp": null,
+        "cdp": null,
+        "mdns": null,
+        "dhcpv4_client": null,
+        "dhcpv6_client": null,
+        "dhcpv4_server": null,
+        "dhcpv6_server": null
+      },
+      "prefixes": [],
+      "vlans": [
+        {
+          "first_seen": "0001-01-01T00:00:00Z",
+          "last_seen": "0001-01-01T00:00:00Z",
+          "type": "VLAN",
+          "id": 42,
+          "packets": 1
+        }
+      ],
+      "vxlans": [],
+      "geneves": [],
+      "unicast_addresses": [
+        {
+          "addr": "192.0.2.1",
+          "first_seen": "0001-01-01T00:00:00Z",
+          "last_seen": "0001-01-01T00:00:00Z",
+          "packets": 1
+        }
+      ],
+      "tentative_addresses": [],
+      "multicast_addresses": [],
+      "mac_peers": [],
+      "ip_peers": []
+    }
+  ],
+  "alerts": []
+}
+`
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
+
+func TestDeviceMapExpire(t *testing.T) {
+	var d DeviceMap
+	var want, got *DeviceInfo
+
+	// prepare macs
+	m1, err := net.ParseMAC("00:00:5e:00:53:01")
+	if err != nil {
+		log.Fatal(err)
+	}
+	m2, err := net.ParseMAC("00:00:5e:00:53:02")
+	if err != nil {
+		log.Fatal(err)
+	}
+	old := layers.NewMACEndpoint(m1)
+	cur := layers.NewMACEndpoint(m2)
+
+	// add devices, current device with old vlan
+	d.Add(old).SetTimestamp(time.Now().Add(-2 * time.Minute))
+	device := d.Add(cur)
+	device.SetTimestamp(time.Now())
+	device.VLANs.Add(42, "VLAN").SetTimestamp(
+		time.Now().Add(-2 * time.Minute))
+
+	// expire and test
+	d.Expire(time.Minute)
+	want = nil
+	got = d.Get(old)
+	if got != want {
+		t.Errorf("got = %p; want = %p", got, want)
+	}
+	want = device
+	got = d.Get(cur)
+	if got != want {
+		t.Errorf("got = %p; want = %p", got, want)
+	}
+	if device.VLANs.Len() != 0 {
+		t.Errorf("device.VLANs.Len() = %d; want 0", device.VLANs.Len())
+	}
+}
+
+func TestDeviceMapPrintFilter(t *testing.T) {
+	var d DeviceMap
+	var buf bytes.Buffer
+	var want, got string
+
+	// add devices with vendors
+	d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})).Vendor = "Vendor B"
+	d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})).Vendor = "Vendor A"
+	d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 3})).Vendor = "Other"
+
+	// test vendor filter and sort
+	d.PrintFilter(&buf, &Filter{Vendor: "vendor", Sort: SortVendor})
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 2                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 00:00:5e:00:53:02 (Vendor A)                " +
+		"(age: -1, pkts: 0)\n\n" +
+		"MAC: 00:00:5e:00:53:01 (Vendor B)                " +
+		"(age: -1, pkts: 0)\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
+
+func TestDeviceMapUpdateVendors(t *testing.T) {
+	var d DeviceMap
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	d.Add(mac)
+
+	// load oui database and update vendors
+	db := &OUIDB{}
+	db.Add(0x00005e, 24, "Vendor")
+	SetOUIDB(db)
+	defer SetOUIDB(nil)
+	d.UpdateVendors()
+	if v := d.Get(mac).Vendor; v != "Vendor" {
+		t.Errorf("got = %s; want Vendor", v)
+	}
+}
+
+func TestDeviceMapDelete(t *testing.T) {
+	var d DeviceMap
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	d.Add(mac)
+	devices := d.Devices(nil)
+	if len(devices) != 1 {
+		t.Fatalf("got = %d; want 1", len(devices))
+	}
+	d.Delete(devices[0])
+	if d.Get(mac) != nil || len(d.Devices(nil)) != 0 {
+		t.Errorf("got = %v; want nil", d.Get(mac))
+	}
+}
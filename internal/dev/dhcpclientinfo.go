@@ -0,0 +1,45 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+)
+
+// DHCPClientInfo stores the information a DHCP client sends in its requests
+type DHCPClientInfo struct {
+	TimeInfo
+	Name        string `json:"name"`
+	Hostname    string `json:"hostname"`
+	FQDN        string `json:"fqdn"`
+	VendorClass string `json:"vendor_class"`
+	ClientID    string `json:"client_id"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Print prints the DHCP client info to w
+func (d *DHCPClientInfo) Print(w io.Writer) {
+	if d == nil {
+		return
+	}
+	clientFmt := "    %s: %-*t (age: %.f)\n"
+	padLen := 42 - len(d.Name)
+	fmt.Fprintf(w, clientFmt, d.Name, padLen, true, d.Age())
+
+	// print all fields that are set
+	valueFmt := "      %s: %s\n"
+	if d.Hostname != "" {
+		fmt.Fprintf(w, valueFmt, "Hostname", d.Hostname)
+	}
+	if d.FQDN != "" {
+		fmt.Fprintf(w, valueFmt, "FQDN", d.FQDN)
+	}
+	if d.VendorClass != "" {
+		fmt.Fprintf(w, valueFmt, "Vendor Class", d.VendorClass)
+	}
+	if d.ClientID != "" {
+		fmt.Fprintf(w, valueFmt, "Client ID", d.ClientID)
+	}
+	if d.Fingerprint != "" {
+		fmt.Fprintf(w, valueFmt, "Fingerprint", d.Fingerprint)
+	}
+}
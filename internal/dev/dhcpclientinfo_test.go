@@ -0,0 +1,42 @@
+package dev
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDHCPClientInfoPrint(t *testing.T) {
+	var d *DHCPClientInfo
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	d.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	d = &DHCPClientInfo{
+		Name:        "DHCPv4 Client",
+		Hostname:    "host",
+		FQDN:        "host.example.com",
+		VendorClass: "MSFT 5.0",
+		ClientID:    "01:00:00:5e:00:53:01",
+		Fingerprint: "1,3,6,15",
+	}
+	d.Print(&buf)
+	want = "    DHCPv4 Client: true                          (age: -1)\n" +
+		"      Hostname: host\n" +
+		"      FQDN: host.example.com\n" +
+		"      Vendor Class: MSFT 5.0\n" +
+		"      Client ID: 01:00:00:5e:00:53:01\n" +
+		"      Fingerprint: 1,3,6,15\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DHCPServerInfo stores the configuration a DHCP server offers to clients
+type DHCPServerInfo struct {
+	TimeInfo
+	Name         string   `json:"name"`
+	ServerID     string   `json:"server_id"`
+	Address      string   `json:"offered_address"`
+	SubnetMask   string   `json:"subnet_mask"`
+	Routers      []string `json:"routers"`
+	DNSServers   []string `json:"dns_servers"`
+	DomainName   string   `json:"domain_name"`
+	DomainSearch []string `json:"domain_search"`
+	LeaseTime    uint32   `json:"lease_time"`
+}
+
+// Print prints the DHCP server info to w
+func (d *DHCPServerInfo) Print(w io.Writer) {
+	if d == nil {
+		return
+	}
+	serverFmt := "    %s: %-*t (age: %.f)\n"
+	padLen := 42 - len(d.Name)
+	fmt.Fprintf(w, serverFmt, d.Name, padLen, true, d.Age())
+
+	// print all fields that are set
+	valueFmt := "      %s: %s\n"
+	if d.ServerID != "" {
+		fmt.Fprintf(w, valueFmt, "Server ID", d.ServerID)
+	}
+	if d.Address != "" {
+		fmt.Fprintf(w, valueFmt, "Offered Address", d.Address)
+	}
+	if d.SubnetMask != "" {
+		fmt.Fprintf(w, valueFmt, "Subnet Mask", d.SubnetMask)
+	}
+	for _, router := range d.Routers {
+		fmt.Fprintf(w, valueFmt, "Router", router)
+	}
+	for _, server := range d.DNSServers {
+		fmt.Fprintf(w, valueFmt, "DNS Server", server)
+	}
+	if d.DomainName != "" {
+		fmt.Fprintf(w, valueFmt, "Domain Name", d.DomainName)
+	}
+	if len(d.DomainSearch) > 0 {
+		fmt.Fprintf(w, valueFmt, "Domain Search",
+			strings.Join(d.DomainSearch, ", "))
+	}
+	if d.LeaseTime != 0 {
+		fmt.Fprintf(w, "      Lease Time: %d\n", d.LeaseTime)
+	}
+}
+
+// Reset removes the configuration from the DHCP server info, so it only
+// contains the configuration of the next message
+func (d *DHCPServerInfo) Reset() {
+	*d = DHCPServerInfo{
+		TimeInfo: d.TimeInfo,
+		Name:     d.Name,
+	}
+}
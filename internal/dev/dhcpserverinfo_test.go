@@ -0,0 +1,65 @@
+package dev
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDHCPServerInfoPrint(t *testing.T) {
+	var d *DHCPServerInfo
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	d.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	d = &DHCPServerInfo{
+		Name:         "DHCPv4 Server",
+		ServerID:     "192.0.2.1",
+		Address:      "192.0.2.100",
+		SubnetMask:   "255.255.255.0",
+		Routers:      []string{"192.0.2.1"},
+		DNSServers:   []string{"192.0.2.53", "192.0.2.54"},
+		DomainName:   "example.com",
+		DomainSearch: []string{"example.com", "example.org"},
+		LeaseTime:    86400,
+	}
+	d.Print(&buf)
+	want = "    DHCPv4 Server: true                          (age: -1)\n" +
+		"      Server ID: 192.0.2.1\n" +
+		"      Offered Address: 192.0.2.100\n" +
+		"      Subnet Mask: 255.255.255.0\n" +
+		"      Router: 192.0.2.1\n" +
+		"      DNS Server: 192.0.2.53\n" +
+		"      DNS Server: 192.0.2.54\n" +
+		"      Domain Name: example.com\n" +
+		"      Domain Search: example.com, example.org\n" +
+		"      Lease Time: 86400\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestDHCPServerInfoReset(t *testing.T) {
+	d := &DHCPServerInfo{
+		Name:       "DHCPv4 Server",
+		ServerID:   "192.0.2.1",
+		Address:    "192.0.2.100",
+		DNSServers: []string{"192.0.2.53"},
+		LeaseTime:  3600,
+	}
+	d.Reset()
+	want := &DHCPServerInfo{Name: "DHCPv4 Server"}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("got = %v; want = %v", d, want)
+	}
+}
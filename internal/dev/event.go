@@ -0,0 +1,82 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gopacket/gopacket"
+)
+
+// event types
+const (
+	EventDeviceAdded     = "device-added"
+	EventDeviceRemoved   = "device-removed"
+	EventAddressAdded    = "address-added"
+	EventAddressRemoved  = "address-removed"
+	EventMulticastJoin   = "multicast-join"
+	EventMulticastLeave  = "multicast-leave"
+	EventPropertyChanged = "property-changed"
+	EventVNetSeen        = "vnet-seen"
+)
+
+// Event is a change of a device in the device table
+type Event struct {
+	Time   time.Time
+	Type   string
+	Device gopacket.Endpoint
+	VLAN   uint32
+	Value  string
+}
+
+// String converts the event to a string
+func (e *Event) String() string {
+	s := fmt.Sprintf("%s: device %s, vlan %d", e.Type, e.Device, e.VLAN)
+	if e.Value != "" {
+		s += ": " + e.Value
+	}
+	return s
+}
+
+// MarshalJSON converts the event to json
+func (e *Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Time   time.Time `json:"time"`
+		Type   string    `json:"type"`
+		Device string    `json:"device"`
+		VLAN   uint32    `json:"vlan"`
+		Value  string    `json:"value"`
+	}{
+		Time:   e.Time,
+		Type:   e.Type,
+		Device: e.Device.String(),
+		VLAN:   e.VLAN,
+		Value:  e.Value,
+	})
+}
+
+// eventEmitter emits events of a device in the device table
+type eventEmitter struct {
+	devices *DeviceMap
+	device  *DeviceInfo
+}
+
+// emit sends an event of type typ with value to the event bus of the device
+// table; the vlan is the vlan of the device if devices are keyed by vlan,
+// otherwise the vlan of the packet that is currently parsed
+func (e *eventEmitter) emit(typ, value string) {
+	if e == nil {
+		return
+	}
+	vlan := e.devices.vlan
+	if vlanMode {
+		vlan = e.device.VLAN
+	}
+	e.devices.Events.publish(&Event{
+		Time:   clock.Now(),
+		Type:   typ,
+		Device: e.device.MAC,
+		VLAN:   vlan,
+		Value:  value,
+	})
+}
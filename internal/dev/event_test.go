@@ -0,0 +1,40 @@
+package dev
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestEvent(t *testing.T) {
+	e := &Event{
+		Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Type: EventAddressAdded,
+		Device: layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}),
+		VLAN:  10,
+		Value: "192.0.2.1",
+	}
+
+	// test string
+	want := "address-added: device 00:00:5e:00:53:01, vlan 10: 192.0.2.1"
+	got := e.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test json
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"time":"2020-01-02T03:04:05Z","type":"address-added",` +
+		`"device":"00:00:5e:00:53:01","vlan":10,"value":"192.0.2.1"}`
+	got = string(b)
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
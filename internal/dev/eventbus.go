@@ -0,0 +1,35 @@
+package dev
+
+// EventSink is the interface for receivers of events; sinks are called
+// while the device table is locked, so they must not block
+type EventSink interface {
+	HandleEvent(event *Event)
+}
+
+// EventBus forwards events to event sinks; adding and removing sinks
+// requires holding the lock of the device table
+type EventBus struct {
+	sinks []EventSink
+}
+
+// AddSink adds an event sink that is called for all events
+func (e *EventBus) AddSink(sink EventSink) {
+	e.sinks = append(e.sinks, sink)
+}
+
+// RemoveSink removes the event sink
+func (e *EventBus) RemoveSink(sink EventSink) {
+	for i, s := range e.sinks {
+		if s == sink {
+			e.sinks = append(e.sinks[:i], e.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish forwards the event to all event sinks
+func (e *EventBus) publish(event *Event) {
+	for _, sink := range e.sinks {
+		sink.HandleEvent(event)
+	}
+}
@@ -0,0 +1,115 @@
+package dev
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+// testEventRecorder is an event sink that records events
+type testEventRecorder struct {
+	events []string
+}
+
+// HandleEvent records the event
+func (t *testEventRecorder) HandleEvent(event *Event) {
+	t.events = append(t.events, event.String())
+}
+
+func TestEventBus(t *testing.T) {
+	var d DeviceMap
+	sink := &testEventRecorder{}
+	d.Events.AddSink(sink)
+
+	// change device table
+	d.SetVLAN(10)
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	ip := layers.NewIPEndpoint(net.ParseIP("192.0.2.1"))
+	group := layers.NewIPEndpoint(net.ParseIP("224.0.0.251"))
+	device := d.Add(mac)
+	d.Add(mac)
+	device.UCasts.Add(ip).SetTimestamp(time.Now().Add(-time.Hour))
+	device.UCasts.Add(ip)
+	device.MCasts.Add(group)
+	device.MCasts.Del(group)
+	device.Router.Enable()
+	device.Router.Enable()
+	device.VLANs.Add(10, "VLAN")
+	device.MACPeers.Add(mac)
+	device.SetTimestamp(time.Now())
+	d.Expire(time.Minute)
+
+	want := []string{
+		"device-added: device 00:00:5e:00:53:01, vlan 10",
+		"address-added: device 00:00:5e:00:53:01, vlan 10: 192.0.2.1",
+		"multicast-join: device 00:00:5e:00:53:01, vlan 10: " +
+			"224.0.0.251",
+		"multicast-leave: device 00:00:5e:00:53:01, vlan 10: " +
+			"224.0.0.251",
+		"property-changed: device 00:00:5e:00:53:01, vlan 10: " +
+			"Router: true",
+		"vnet-seen: device 00:00:5e:00:53:01, vlan 10: VLAN 10",
+		"address-removed: device 00:00:5e:00:53:01, vlan 0: " +
+			"192.0.2.1",
+	}
+	if len(sink.events) != len(want) {
+		t.Fatalf("got = %v; want %v", sink.events, want)
+	}
+	for i := range want {
+		if sink.events[i] != want[i] {
+			t.Errorf("got = %s; want %s", sink.events[i], want[i])
+		}
+	}
+
+	// remove sink, no more events
+	d.Events.RemoveSink(sink)
+	d.Add(layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}))
+	if len(sink.events) != len(want) {
+		t.Errorf("got = %d; want %d", len(sink.events), len(want))
+	}
+}
+
+func TestEventBusDeviceRemoved(t *testing.T) {
+	var d DeviceMap
+	sink := &testEventRecorder{}
+	d.Events.AddSink(sink)
+
+	// vnets with same id and different types
+	d.SetVLAN(10)
+	mac1 := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	mac2 := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})
+	mac3 := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 3})
+	device := d.Add(mac1)
+	device.VLANs.Add(42, "VLAN")
+	device.VXLANs.Add(42, "VXLAN")
+
+	// expire, delete and reset devices
+	device.SetTimestamp(time.Now().Add(-time.Hour))
+	d.Expire(time.Minute)
+	d.SetVLAN(10)
+	d.Delete(d.Add(mac2))
+	d.SetVLAN(10)
+	d.Add(mac3)
+	d.Reset()
+
+	want := []string{
+		"device-added: device 00:00:5e:00:53:01, vlan 10",
+		"vnet-seen: device 00:00:5e:00:53:01, vlan 10: VLAN 42",
+		"vnet-seen: device 00:00:5e:00:53:01, vlan 10: VXLAN 42",
+		"device-removed: device 00:00:5e:00:53:01, vlan 0",
+		"device-added: device 00:00:5e:00:53:02, vlan 10",
+		"device-removed: device 00:00:5e:00:53:02, vlan 0",
+		"device-added: device 00:00:5e:00:53:03, vlan 10",
+		"device-removed: device 00:00:5e:00:53:03, vlan 0",
+	}
+	if len(sink.events) != len(want) {
+		t.Fatalf("got = %v; want %v", sink.events, want)
+	}
+	for i := range want {
+		if sink.events[i] != want[i] {
+			t.Errorf("got = %s; want %s", sink.events[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dev
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+// sort orders of the device table
+const (
+	SortMAC    = "mac"
+	SortVendor = "vendor"
+)
+
+// Filter selects and orders the devices in the device table output
+type Filter struct {
+	MAC       net.HardwareAddr
+	IP        net.IP
+	VLAN      *uint32
+	Property  string
+	Vendor    string
+	Interface string
+	MaxAge    time.Duration
+	Sort      string
+}
+
+// IsValidSort checks if the sort order s is supported
+func IsValidSort(s string) bool {
+	switch s {
+	case "", SortMAC, SortVendor:
+		return true
+	}
+	return false
+}
+
+// Match checks if device d matches the filter
+func (f *Filter) Match(d *DeviceInfo) bool {
+	if f == nil {
+		return true
+	}
+	if f.MAC != nil && d.MAC != layers.NewMACEndpoint(f.MAC) {
+		return false
+	}
+	if f.IP != nil && d.UCasts.Get(layers.NewIPEndpoint(f.IP)) == nil {
+		return false
+	}
+	if f.VLAN != nil && !d.InVLAN(*f.VLAN) {
+		return false
+	}
+	if f.Property != "" && !d.HasProperty(f.Property) {
+		return false
+	}
+	if f.Vendor != "" && !strings.Contains(strings.ToLower(d.Vendor),
+		strings.ToLower(f.Vendor)) {
+		return false
+	}
+	if f.Interface != "" && d.Interfaces.Get(f.Interface) == nil {
+		return false
+	}
+	if f.MaxAge > 0 && (d.Timestamp.IsZero() || d.IsExpired(f.MaxAge)) {
+		return false
+	}
+	return true
+}
+
+// Less checks if device a is ordered before device b
+func (f *Filter) Less(a, b *DeviceInfo) bool {
+	if f != nil && f.Sort == SortVendor && a.Vendor != b.Vendor {
+		return a.Vendor < b.Vendor
+	}
+	return a.MAC.LessThan(b.MAC)
+}
@@ -0,0 +1,101 @@
+package dev
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestFilter(t *testing.T) {
+	a := &DeviceInfo{
+		MAC: layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}),
+		Vendor: "Vendor B",
+	}
+	b := &DeviceInfo{
+		MAC: layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}),
+		Vendor: "Vendor A",
+	}
+
+	// test nil filter
+	var f *Filter
+	if !f.Match(a) || !f.Less(a, b) {
+		t.Errorf("nil filter should match and sort by mac")
+	}
+
+	// test vendor filter
+	f = &Filter{Vendor: "vendor b"}
+	if !f.Match(a) || f.Match(b) {
+		t.Errorf("got = %t, %t; want true, false", f.Match(a),
+			f.Match(b))
+	}
+
+	// test interface filter
+	b.Interfaces.Add("eth1")
+	f = &Filter{Interface: "eth1"}
+	if f.Match(a) || !f.Match(b) {
+		t.Errorf("got = %t, %t; want false, true", f.Match(a),
+			f.Match(b))
+	}
+
+	// test vendor sort
+	f = &Filter{Sort: SortVendor}
+	if f.Less(a, b) || !f.Less(b, a) {
+		t.Errorf("got = %t, %t; want false, true", f.Less(a, b),
+			f.Less(b, a))
+	}
+
+	// test valid sort
+	for _, s := range []string{"", SortMAC, SortVendor} {
+		if !IsValidSort(s) {
+			t.Errorf("got = false; want true for %s", s)
+		}
+	}
+	if IsValidSort("invalid") {
+		t.Errorf("got = true; want false")
+	}
+}
+
+func TestFilterDevice(t *testing.T) {
+	a := &DeviceInfo{
+		MAC: layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}),
+	}
+	b := &DeviceInfo{
+		MAC: layers.NewMACEndpoint(
+			net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}),
+	}
+	a.UCasts.Add(layers.NewIPEndpoint(net.ParseIP("192.0.2.1")))
+	a.Router.Enable()
+	a.SetTimestamp(time.Now())
+	b.VLANs.Add(10, "VLAN")
+	b.SetTimestamp(time.Now().Add(-time.Hour))
+	vlan0, vlan10 := uint32(0), uint32(10)
+
+	for _, test := range []struct {
+		f    *Filter
+		a, b bool
+	}{
+		{&Filter{MAC: net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}},
+			false, true},
+		{&Filter{IP: net.ParseIP("192.0.2.1")}, true, false},
+		{&Filter{VLAN: &vlan0}, true, false},
+		{&Filter{VLAN: &vlan10}, false, true},
+		{&Filter{Property: PropertyRouter}, true, false},
+		{&Filter{MaxAge: time.Minute}, true, false},
+	} {
+		if test.f.Match(a) != test.a || test.f.Match(b) != test.b {
+			t.Errorf("%+v: got = %t, %t; want %t, %t", *test.f,
+				test.f.Match(a), test.f.Match(b), test.a,
+				test.b)
+		}
+	}
+
+	// test valid property
+	if !IsValidProperty(PropertyRouter) || IsValidProperty("invalid") {
+		t.Errorf("got = false, true; want true, false")
+	}
+}
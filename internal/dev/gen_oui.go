@@ -0,0 +1,56 @@
+//go:build ignore
+
+// gen_oui downloads the IEEE MA-L registry and writes it as gzip compressed
+// csv file to oui.csv.gz, the embedded fallback snapshot of the oui database
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+var (
+	url = flag.String("url", "https://standards-oui.ieee.org/oui/oui.csv",
+		"download IEEE MA-L registry in csv format from `url`")
+	file = flag.String("o", "oui.csv.gz", "write snapshot to `file`")
+)
+
+// download downloads the registry and writes it to w
+func download(w io.Writer) error {
+	resp, err := http.Get(*url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error downloading %s: %s", *url, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func main() {
+	flag.Parse()
+	f, err := os.Create(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	zw, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := download(zw); err != nil {
+		log.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,17 @@
+package dev
+
+import "fmt"
+
+// IfaceInfo stores information about a network interface or pcap file a
+// device was seen on
+type IfaceInfo struct {
+	TimeInfo
+	Name    string `json:"name"`
+	Packets int    `json:"packets"`
+}
+
+// String converts interface info to a string
+func (i *IfaceInfo) String() string {
+	ifaceFmt := "Interface: %-33s (age: %.f, pkts: %d)"
+	return fmt.Sprintf(ifaceFmt, i.Name, i.Age(), i.Packets)
+}
@@ -0,0 +1,26 @@
+package dev
+
+import "testing"
+
+func TestIfaceInfo(t *testing.T) {
+	var i IfaceInfo
+	var want, got string
+
+	// test default
+	want = "Interface:                                   " +
+		"(age: -1, pkts: 0)"
+	got = i.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test filled
+	i.Name = "eth0"
+	i.Packets = 128
+	want = "Interface: eth0                              " +
+		"(age: -1, pkts: 128)"
+	got = i.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
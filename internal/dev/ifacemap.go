@@ -0,0 +1,93 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// IfaceMap stores mappings from interface names to interface information
+type IfaceMap struct {
+	m map[string]*IfaceInfo
+}
+
+// Add adds an interface with name to the mapping and returns the interface
+// info
+func (i *IfaceMap) Add(name string) *IfaceInfo {
+	if i.m == nil {
+		i.m = make(map[string]*IfaceInfo)
+	}
+	if i.m[name] == nil {
+		debug("Adding new interface entry")
+		iface := IfaceInfo{
+			Name: name,
+		}
+		i.m[name] = &iface
+	}
+	return i.m[name]
+}
+
+// Get returns the interface info with name
+func (i *IfaceMap) Get(name string) *IfaceInfo {
+	if i.m == nil {
+		return nil
+	}
+	return i.m[name]
+}
+
+// Len returns the number of interfaces in the interface map
+func (i *IfaceMap) Len() int {
+	return len(i.m)
+}
+
+// Expire removes all interface infos not seen within timeout
+func (i *IfaceMap) Expire(timeout time.Duration) {
+	for name, iface := range i.m {
+		if iface.IsExpired(timeout) {
+			debug("Expiring interface entry")
+			delete(i.m, name)
+		}
+	}
+}
+
+// sorted returns all interface infos sorted by name
+func (i *IfaceMap) sorted() []*IfaceInfo {
+	ifaces := make([]*IfaceInfo, 0, len(i.m))
+	for _, iface := range i.m {
+		ifaces = append(ifaces, iface)
+	}
+	sort.Slice(ifaces, func(a, b int) bool {
+		return ifaces[a].Name < ifaces[b].Name
+	})
+	return ifaces
+}
+
+// Print prints the interface map to w
+func (i *IfaceMap) Print(w io.Writer) {
+	if len(i.m) > 0 {
+		fmt.Fprintf(w, "  Interfaces:\n")
+		for _, iface := range i.sorted() {
+			fmt.Fprintf(w, "    %s\n", iface)
+		}
+	}
+}
+
+// MarshalJSON converts the interface map to a json array sorted by name
+func (i *IfaceMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.sorted())
+}
+
+// UnmarshalJSON restores the interface map from a json array
+func (i *IfaceMap) UnmarshalJSON(data []byte) error {
+	var ifaces []*IfaceInfo
+	if err := json.Unmarshal(data, &ifaces); err != nil {
+		return err
+	}
+	i.m = make(map[string]*IfaceInfo)
+	for _, iface := range ifaces {
+		i.m[iface.Name] = iface
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestIfaceMapAdd(t *testing.T) {
+	var i IfaceMap
+	var want, got *IfaceInfo
+
+	// test empty
+	want = nil
+	got = i.Get("eth0")
+	if got != want {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+
+	// test filled
+	want = i.Add("eth0")
+	got = i.Get("eth0")
+	if got != want {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+	if i.Len() != 1 {
+		t.Errorf("got = %d; want 1", i.Len())
+	}
+}
+
+func TestIfaceMapExpire(t *testing.T) {
+	var i IfaceMap
+	i.Add("eth0").SetTimestamp(time.Now().Add(-time.Hour))
+	i.Add("eth1").SetTimestamp(time.Now())
+	i.Expire(time.Minute)
+	if i.Get("eth0") != nil || i.Get("eth1") == nil {
+		t.Errorf("got = %v, %v; want nil, eth1", i.Get("eth0"),
+			i.Get("eth1"))
+	}
+}
+
+func TestIfaceMapPrint(t *testing.T) {
+	var i IfaceMap
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	i.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test filled
+	i.Add("eth1").Packets = 2
+	i.Add("eth0").Packets = 1
+	i.Print(&buf)
+	want = "  Interfaces:\n" +
+		"    Interface: eth0                              " +
+		"(age: -1, pkts: 1)\n" +
+		"    Interface: eth1                              " +
+		"(age: -1, pkts: 2)\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestIfaceMapJSON(t *testing.T) {
+	var i, r IfaceMap
+	i.Add("eth1").Packets = 2
+	i.Add("eth0").Packets = 1
+	b, err := json.Marshal(&i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 2 || r.Get("eth1").Packets != 2 {
+		t.Errorf("got = %v; want %v", r.m, i.m)
+	}
+}
@@ -0,0 +1,69 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LLDPVLAN stores a vlan advertised with LLDP
+type LLDPVLAN struct {
+	ID   uint16 `json:"id"`
+	Name string `json:"name"`
+}
+
+// LLDPInfo stores the LLDP information advertised by a device
+type LLDPInfo struct {
+	TimeInfo
+	ChassisID       string     `json:"chassis_id"`
+	PortID          string     `json:"port_id"`
+	PortDescription string     `json:"port_description"`
+	SysName         string     `json:"system_name"`
+	SysDescription  string     `json:"system_description"`
+	SysCapabilities []string   `json:"system_capabilities"`
+	MgmtAddresses   []string   `json:"management_addresses"`
+	PortVLANID      uint16     `json:"port_vlan_id"`
+	VLANs           []LLDPVLAN `json:"vlans"`
+}
+
+// Print prints the LLDP info to w
+func (l *LLDPInfo) Print(w io.Writer) {
+	if l == nil {
+		return
+	}
+	lldpFmt := "    LLDP: %-38t (age: %.f)\n"
+	fmt.Fprintf(w, lldpFmt, true, l.Age())
+
+	// print all fields that are set
+	valueFmt := "      %s: %s\n"
+	if l.ChassisID != "" {
+		fmt.Fprintf(w, valueFmt, "Chassis ID", l.ChassisID)
+	}
+	if l.PortID != "" {
+		fmt.Fprintf(w, valueFmt, "Port ID", l.PortID)
+	}
+	if l.PortDescription != "" {
+		fmt.Fprintf(w, valueFmt, "Port Description",
+			l.PortDescription)
+	}
+	if l.SysName != "" {
+		fmt.Fprintf(w, valueFmt, "System Name", l.SysName)
+	}
+	if l.SysDescription != "" {
+		fmt.Fprintf(w, valueFmt, "System Description",
+			l.SysDescription)
+	}
+	if len(l.SysCapabilities) > 0 {
+		fmt.Fprintf(w, valueFmt, "System Capabilities",
+			strings.Join(l.SysCapabilities, ", "))
+	}
+	for _, addr := range l.MgmtAddresses {
+		fmt.Fprintf(w, valueFmt, "Management Address", addr)
+	}
+	if l.PortVLANID != 0 {
+		fmt.Fprintf(w, "      Port VLAN ID: %d\n", l.PortVLANID)
+	}
+	for _, vlan := range l.VLANs {
+		fmt.Fprintf(w, "      VLAN: %d (%s)\n", vlan.ID, vlan.Name)
+	}
+}
@@ -0,0 +1,50 @@
+package dev
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLLDPInfoPrint(t *testing.T) {
+	var l *LLDPInfo
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	l.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	l = &LLDPInfo{
+		ChassisID:       "00:00:5e:00:53:01",
+		PortID:          "ge-0/0/1",
+		PortDescription: "uplink",
+		SysName:         "switch1",
+		SysDescription:  "test switch",
+		SysCapabilities: []string{"bridge", "router"},
+		MgmtAddresses:   []string{"192.0.2.1", "2001:db8::1"},
+		PortVLANID:      1,
+		VLANs:           []LLDPVLAN{{ID: 10, Name: "voice"}},
+	}
+	l.Print(&buf)
+	want = "    LLDP: true                                   (age: -1)\n" +
+		"      Chassis ID: 00:00:5e:00:53:01\n" +
+		"      Port ID: ge-0/0/1\n" +
+		"      Port Description: uplink\n" +
+		"      System Name: switch1\n" +
+		"      System Description: test switch\n" +
+		"      System Capabilities: bridge, router\n" +
+		"      Management Address: 192.0.2.1\n" +
+		"      Management Address: 2001:db8::1\n" +
+		"      Port VLAN ID: 1\n" +
+		"      VLAN: 10 (voice)\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
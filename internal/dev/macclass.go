@@ -0,0 +1,72 @@
+package dev
+
+import (
+	"bytes"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+// mac address classes
+const (
+	MACClassGlobal     = "globally unique"
+	MACClassLocal      = "locally administered"
+	MACClassRandomized = "randomized"
+	MACClassMulticast  = "multicast"
+	MACClassVirtual    = "virtual"
+)
+
+// mac address bits
+const (
+	macBitMulticast = 0x01
+	macBitLocal     = 0x02
+)
+
+// macVirtualPrefixes are well-known prefixes of virtual mac addresses
+var macVirtualPrefixes = [][]byte{
+	{0x00, 0x00, 0x5e, 0x00, 0x01}, // VRRP IPv4
+	{0x00, 0x00, 0x5e, 0x00, 0x02}, // VRRP IPv6
+	{0x00, 0x00, 0x0c, 0x07, 0xac}, // HSRPv1
+	{0x00, 0x05, 0x69},             // VMware
+	{0x00, 0x0c, 0x29},             // VMware
+	{0x00, 0x1c, 0x14},             // VMware
+	{0x00, 0x50, 0x56},             // VMware
+	{0x08, 0x00, 0x27},             // VirtualBox
+	{0x00, 0x15, 0x5d},             // Hyper-V
+	{0x00, 0x16, 0x3e},             // Xen
+	{0x00, 0x1c, 0x42},             // Parallels
+	{0x52, 0x54, 0x00},             // QEMU/KVM
+	{0x02, 0x42},                   // Docker
+}
+
+// ClassifyMAC returns the class of the mac address in endpoint mac; locally
+// administered addresses in the IEEE SLAP ELI or SAI quadrants are
+// considered locally administered, all other ones randomized
+func ClassifyMAC(mac gopacket.Endpoint) string {
+	raw := mac.Raw()
+	if mac.EndpointType() != layers.EndpointMAC || len(raw) != 6 {
+		return ""
+	}
+	if raw[0]&macBitMulticast != 0 {
+		return MACClassMulticast
+	}
+	for _, prefix := range macVirtualPrefixes {
+		if bytes.HasPrefix(raw, prefix) {
+			return MACClassVirtual
+		}
+	}
+	// HSRPv2 uses 00:00:0c:9f:f0:00 - 00:00:0c:9f:ff:ff
+	if bytes.HasPrefix(raw, []byte{0x00, 0x00, 0x0c, 0x9f}) &&
+		raw[4]&0xf0 == 0xf0 {
+		return MACClassVirtual
+	}
+	if raw[0]&macBitLocal == 0 {
+		return MACClassGlobal
+	}
+	switch raw[0] & 0x0f {
+	case 0x0a, 0x0e:
+		// SLAP extended local (ELI) or standard assigned (SAI)
+		return MACClassLocal
+	}
+	return MACClassRandomized
+}
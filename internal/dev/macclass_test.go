@@ -0,0 +1,45 @@
+package dev
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestClassifyMAC(t *testing.T) {
+	tests := []struct {
+		mac  string
+		want string
+	}{
+		{"00:00:5e:00:53:01", MACClassGlobal},
+		{"01:00:5e:00:00:fb", MACClassMulticast},
+		{"00:00:5e:00:01:01", MACClassVirtual},
+		{"00:00:0c:07:ac:01", MACClassVirtual},
+		{"00:00:0c:9f:f0:01", MACClassVirtual},
+		{"00:50:56:00:00:01", MACClassVirtual},
+		{"52:54:00:00:00:01", MACClassVirtual},
+		{"02:42:ac:11:00:02", MACClassVirtual},
+		{"d2:a1:19:00:00:01", MACClassRandomized},
+		{"06:00:00:00:00:01", MACClassRandomized},
+		{"0a:00:00:00:00:01", MACClassLocal},
+		{"ae:00:00:00:00:01", MACClassLocal},
+	}
+	for _, test := range tests {
+		mac, err := net.ParseMAC(test.mac)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ClassifyMAC(layers.NewMACEndpoint(mac))
+		if got != test.want {
+			t.Errorf("%s: got = %s; want %s", test.mac, got,
+				test.want)
+		}
+	}
+
+	// test non-mac endpoint
+	got := ClassifyMAC(layers.NewIPEndpoint(net.IP{192, 0, 2, 1}))
+	if got != "" {
+		t.Errorf("got = %s; want ", got)
+	}
+}
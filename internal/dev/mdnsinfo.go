@@ -0,0 +1,115 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MDNSService stores a service instance announced with mDNS/DNS-SD
+type MDNSService struct {
+	TimeInfo
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+	Host string   `json:"host"`
+	Port uint16   `json:"port"`
+	TXT  []string `json:"txt"`
+}
+
+// String converts the mDNS service to a string
+func (s *MDNSService) String() string {
+	if s.Host == "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%s (%s:%d)", s.Name, s.Host, s.Port)
+}
+
+// MDNSInfo stores the hostnames and services a device announces with mDNS
+type MDNSInfo struct {
+	TimeInfo
+	Hostnames []string                `json:"hostnames"`
+	Services  map[string]*MDNSService `json:"services"`
+}
+
+// AddHostname adds hostname to the mDNS info
+func (m *MDNSInfo) AddHostname(hostname string) {
+	for _, h := range m.Hostnames {
+		if h == hostname {
+			return
+		}
+	}
+	debug("Adding new mDNS hostname")
+	m.Hostnames = append(m.Hostnames, hostname)
+}
+
+// AddService adds the service instance with name to the mDNS info and
+// returns the service
+func (m *MDNSInfo) AddService(name string) *MDNSService {
+	if m.Services == nil {
+		m.Services = make(map[string]*MDNSService)
+	}
+	if m.Services[name] == nil {
+		debug("Adding new mDNS service")
+
+		// service instance names look like
+		// "<instance>.<service>.<proto>.<domain>", e.g.,
+		// "printer._ipp._tcp.local", get type from it
+		service := MDNSService{
+			Name: name,
+		}
+		if i := strings.Index(name, "._"); i >= 0 {
+			service.Type = name[i+1:]
+		}
+		m.Services[name] = &service
+	}
+	return m.Services[name]
+}
+
+// DelService removes the service instance with name from the mDNS info
+func (m *MDNSInfo) DelService(name string) {
+	if m.Services[name] != nil {
+		debug("Deleting mDNS service")
+		delete(m.Services, name)
+	}
+}
+
+// Expire removes all services not seen within timeout
+func (m *MDNSInfo) Expire(timeout time.Duration) {
+	for name, service := range m.Services {
+		if service.IsExpired(timeout) {
+			debug("Expiring mDNS service")
+			delete(m.Services, name)
+		}
+	}
+}
+
+// Print prints the mDNS info to w
+func (m *MDNSInfo) Print(w io.Writer) {
+	if m == nil {
+		return
+	}
+	mdnsFmt := "    mDNS: %-38t (age: %.f)\n"
+	fmt.Fprintf(w, mdnsFmt, true, m.Age())
+
+	// print hostnames
+	for _, hostname := range m.Hostnames {
+		fmt.Fprintf(w, "      Hostname: %s\n", hostname)
+	}
+
+	// print services sorted by name
+	var names []string
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		service := m.Services[name]
+		fmt.Fprintf(w, "      Service: %s\n", service)
+		if len(service.TXT) > 0 {
+			fmt.Fprintf(w, "        TXT: %s\n",
+				strings.Join(service.TXT, ", "))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package dev
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestMDNSInfoAdd(t *testing.T) {
+	var m MDNSInfo
+
+	// test hostnames
+	m.AddHostname("host.local")
+	m.AddHostname("host.local")
+	if len(m.Hostnames) != 1 {
+		t.Errorf("len(m.Hostnames) = %d; want 1", len(m.Hostnames))
+	}
+
+	// test services
+	want := m.AddService("My Printer._ipp._tcp.local")
+	got := m.AddService("My Printer._ipp._tcp.local")
+	if got != want {
+		t.Errorf("got = %p; want %p", got, want)
+	}
+	if got.Type != "_ipp._tcp.local" {
+		t.Errorf("got.Type = %s; want _ipp._tcp.local", got.Type)
+	}
+
+	// test deleting services
+	m.DelService("My Printer._ipp._tcp.local")
+	if len(m.Services) != 0 {
+		t.Errorf("len(m.Services) = %d; want 0", len(m.Services))
+	}
+}
+
+func TestMDNSInfoExpire(t *testing.T) {
+	var m MDNSInfo
+
+	m.AddService("old._ipp._tcp.local").SetTimestamp(
+		time.Now().Add(-2 * time.Minute))
+	m.AddService("new._ipp._tcp.local").SetTimestamp(time.Now())
+	m.Expire(time.Minute)
+	if m.Services["old._ipp._tcp.local"] != nil {
+		t.Errorf("old service not expired")
+	}
+	if m.Services["new._ipp._tcp.local"] == nil {
+		t.Errorf("new service expired")
+	}
+}
+
+func TestMDNSInfoPrint(t *testing.T) {
+	var m *MDNSInfo
+	var buf bytes.Buffer
+	var want, got string
+
+	// test empty
+	m.Print(&buf)
+	want = ""
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	buf.Reset()
+
+	// test filled
+	m = &MDNSInfo{}
+	m.AddHostname("host.local")
+	s := m.AddService("My Printer._ipp._tcp.local")
+	s.Host = "host.local"
+	s.Port = 631
+	s.TXT = []string{"txtvers=1", "ty=Printer"}
+	m.AddService("host._device-info._tcp.local")
+	m.Print(&buf)
+	want = "    mDNS: true                                   (age: -1)\n" +
+		"      Hostname: host.local\n" +
+		"      Service: My Printer._ipp._tcp.local " +
+		"(host.local:631)\n" +
+		"        TXT: txtvers=1, ty=Printer\n" +
+		"      Service: host._device-info._tcp.local\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
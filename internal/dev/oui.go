@@ -0,0 +1,220 @@
+package dev
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+//go:generate go run gen_oui.go
+
+var (
+	// ouiSnapshot is the embedded fallback snapshot of the IEEE MA-L
+	// registry as gzip compressed csv file
+	//go:embed oui.csv.gz
+	ouiSnapshot []byte
+
+	// ouiDB is the oui database used for vendor lookups
+	ouiDB *OUIDB
+)
+
+// ouiPrefixLens are the lengths of IEEE MA-S, MA-M and MA-L assignments in
+// bits, longest first
+var ouiPrefixLens = []int{36, 28, 24}
+
+// OUIDB is a database of IEEE MAC address block assignments to vendors
+type OUIDB struct {
+	m map[int]map[uint64]string
+}
+
+// Add adds the assignment of the prefix with length bits to vendor
+func (o *OUIDB) Add(prefix uint64, bits int, vendor string) {
+	if o.m == nil {
+		o.m = make(map[int]map[uint64]string)
+	}
+	if o.m[bits] == nil {
+		o.m[bits] = make(map[uint64]string)
+	}
+	o.m[bits][prefix] = vendor
+}
+
+// addHex adds the assignment of the prefix in hex string assignment to
+// vendor
+func (o *OUIDB) addHex(assignment, vendor string) error {
+	assignment = strings.ReplaceAll(assignment, "-", "")
+	prefix, err := strconv.ParseUint(assignment, 16, 48)
+	if err != nil || len(assignment) > 12 {
+		return errors.New("invalid oui assignment: " + assignment)
+	}
+	o.Add(prefix, 4*len(assignment), strings.TrimSpace(vendor))
+	return nil
+}
+
+// loadCSV loads assignments in the IEEE csv format from r
+func (o *OUIDB) loadCSV(r io.Reader) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+	for i, record := range records {
+		if len(record) < 3 {
+			return errors.New("invalid oui record")
+		}
+		if i == 0 && record[0] == "Registry" {
+			// skip header
+			continue
+		}
+		if err := o.addHex(record[1], record[2]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRange adds the assignment of the block in hex string block within the
+// MA-L assignment oui to vendor; block is either the MA-L assignment itself,
+// e.g., "00005E", or a range of an MA-M or MA-S block, e.g., "100000-1FFFFF"
+func (o *OUIDB) addRange(oui uint64, block, vendor string) error {
+	first, last, found := strings.Cut(block, "-")
+	if !found {
+		o.Add(oui, 24, strings.TrimSpace(vendor))
+		return nil
+	}
+	start, err1 := strconv.ParseUint(first, 16, 24)
+	end, err2 := strconv.ParseUint(last, 16, 24)
+	size := end - start + 1
+	if err1 != nil || err2 != nil || end < start || size&(size-1) != 0 ||
+		start&(size-1) != 0 {
+		return errors.New("invalid oui block: " + block)
+	}
+	n := 48 - bits.Len64(size-1)
+	o.Add(oui<<(n-24)|start>>(48-n), n, strings.TrimSpace(vendor))
+	return nil
+}
+
+// loadTXT loads assignments in the IEEE txt format from r, i.e., lines like
+// "00-00-5E   (hex)		ICANN, IANA Department" followed by lines like
+// "00005E     (base 16)		ICANN, IANA Department" for MA-L assignments or
+// "100000-1FFFFF     (base 16)		Vendor" for MA-M and MA-S assignments
+func (o *OUIDB) loadTXT(r io.Reader) error {
+	var oui, vendor string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if assignment, v, found := strings.Cut(line, "(hex)"); found {
+			// add previous assignment without block line
+			if oui != "" {
+				if err := o.addHex(oui, vendor); err != nil {
+					return err
+				}
+			}
+			oui, vendor = strings.TrimSpace(assignment), v
+			continue
+		}
+		block, v, found := strings.Cut(line, "(base 16)")
+		if !found || oui == "" {
+			continue
+		}
+		hex := strings.ReplaceAll(oui, "-", "")
+		prefix, err := strconv.ParseUint(hex, 16, 24)
+		if err != nil || len(hex) != 6 {
+			return errors.New("invalid oui assignment: " + oui)
+		}
+		if err := o.addRange(prefix, strings.TrimSpace(block), v); err != nil {
+			return err
+		}
+		oui = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if oui != "" {
+		return o.addHex(oui, vendor)
+	}
+	return nil
+}
+
+// Load loads assignments in the IEEE csv or txt format from r
+func (o *OUIDB) Load(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if bytes.HasPrefix(data, []byte("Registry,")) ||
+		bytes.HasPrefix(data, []byte("MA-")) {
+		return o.loadCSV(bytes.NewReader(data))
+	}
+	return o.loadTXT(bytes.NewReader(data))
+}
+
+// Len returns the number of assignments in the database
+func (o *OUIDB) Len() int {
+	n := 0
+	for _, m := range o.m {
+		n += len(m)
+	}
+	return n
+}
+
+// Lookup returns the vendor of the mac address in endpoint mac
+func (o *OUIDB) Lookup(mac gopacket.Endpoint) string {
+	if o == nil || mac.EndpointType() != layers.EndpointMAC {
+		return ""
+	}
+	raw := mac.Raw()
+	if len(raw) != 6 {
+		return ""
+	}
+	var addr uint64
+	for _, b := range raw {
+		addr = addr<<8 | uint64(b)
+	}
+	for _, bits := range ouiPrefixLens {
+		if vendor, ok := o.m[bits][addr>>(48-bits)]; ok {
+			return vendor
+		}
+	}
+	return ""
+}
+
+// LoadOUIDB loads the oui database from the IEEE registry file in path; if
+// path is empty, it loads the embedded fallback snapshot
+func LoadOUIDB(path string) (*OUIDB, error) {
+	o := &OUIDB{}
+	if path == "" {
+		r, err := gzip.NewReader(bytes.NewReader(ouiSnapshot))
+		if err != nil {
+			return nil, err
+		}
+		if err := o.Load(r); err != nil {
+			return nil, err
+		}
+		return o, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := o.Load(f); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// SetOUIDB sets the oui database used for vendor lookups, nil disables
+// vendor lookups
+func SetOUIDB(db *OUIDB) {
+	ouiDB = db
+}
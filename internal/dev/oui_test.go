@@ -0,0 +1,166 @@
+package dev
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestOUIDBLoadCSV(t *testing.T) {
+	var o OUIDB
+	csv := "Registry,Assignment,Organization Name,Organization Address\n" +
+		"MA-L,00005E,\"ICANN, IANA Department\",\"Los Angeles\"\n" +
+		"MA-M,00005E1,Test MA-M,\n" +
+		"MA-S,00005E123,Test MA-S,\n"
+	if err := o.Load(strings.NewReader(csv)); err != nil {
+		t.Fatal(err)
+	}
+	if o.Len() != 3 {
+		t.Errorf("got = %d; want 3", o.Len())
+	}
+
+	// test longest prefix match
+	tests := []struct {
+		mac  net.HardwareAddr
+		want string
+	}{
+		{net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}, "ICANN, IANA Department"},
+		{net.HardwareAddr{0, 0, 0x5e, 0x10, 0, 1}, "Test MA-M"},
+		{net.HardwareAddr{0, 0, 0x5e, 0x12, 0x30, 1}, "Test MA-S"},
+		{net.HardwareAddr{0, 0, 0x5f, 0, 0, 1}, ""},
+	}
+	for _, test := range tests {
+		got := o.Lookup(layers.NewMACEndpoint(test.mac))
+		if got != test.want {
+			t.Errorf("got = %s; want %s", got, test.want)
+		}
+	}
+
+	// test invalid assignment
+	csv = "MA-L,invalid,Test,\n"
+	if err := o.Load(strings.NewReader(csv)); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
+
+func TestOUIDBLoadTXT(t *testing.T) {
+	var o OUIDB
+	txt := "OUI/MA-L                                                    " +
+		"Organization\n" +
+		"00-00-5E   (hex)\t\tICANN, IANA Department\n" +
+		"00005E     (base 16)\t\tICANN, IANA Department\n" +
+		"\t\t\t\tLos Angeles\n"
+	if err := o.Load(strings.NewReader(txt)); err != nil {
+		t.Fatal(err)
+	}
+	want := "ICANN, IANA Department"
+	got := o.Lookup(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	if got != want || o.Len() != 1 {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestOUIDBLoadTXTBlocks(t *testing.T) {
+	var o OUIDB
+	txt := "OUI-28/MA-M Range                                   " +
+		"Organization\n" +
+		"00-00-5E   (hex)\t\tTest MA-M\n" +
+		"100000-1FFFFF     (base 16)\t\tTest MA-M\n" +
+		"\t\t\t\tSomewhere\n" +
+		"\n" +
+		"00-00-5E   (hex)\t\tTest MA-S\n" +
+		"123000-123FFF     (base 16)\t\tTest MA-S\n"
+	if err := o.Load(strings.NewReader(txt)); err != nil {
+		t.Fatal(err)
+	}
+	if o.Len() != 2 {
+		t.Errorf("got = %d; want 2", o.Len())
+	}
+	tests := []struct {
+		mac  net.HardwareAddr
+		want string
+	}{
+		{net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}, ""},
+		{net.HardwareAddr{0, 0, 0x5e, 0x10, 0, 1}, "Test MA-M"},
+		{net.HardwareAddr{0, 0, 0x5e, 0x1f, 0xff, 0xff}, "Test MA-M"},
+		{net.HardwareAddr{0, 0, 0x5e, 0x12, 0x30, 1}, "Test MA-S"},
+		{net.HardwareAddr{0, 0, 0x5e, 0x12, 0x40, 1}, "Test MA-M"},
+	}
+	for _, test := range tests {
+		got := o.Lookup(layers.NewMACEndpoint(test.mac))
+		if got != test.want {
+			t.Errorf("got = %s; want %s", got, test.want)
+		}
+	}
+
+	// test invalid block
+	txt = "00-00-5E   (hex)\t\tTest\n" +
+		"100000-17FFFE     (base 16)\t\tTest\n"
+	if err := o.Load(strings.NewReader(txt)); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
+
+func TestLoadOUIDB(t *testing.T) {
+	// test embedded snapshot, it contains the full MA-L registry
+	o, err := LoadOUIDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Len() < 20000 {
+		t.Errorf("got = %d; want >= 20000", o.Len())
+	}
+	want := "ICANN, IANA Department"
+	got := o.Lookup(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+	want = "Raspberry Pi Foundation"
+	got = o.Lookup(layers.NewMACEndpoint(
+		net.HardwareAddr{0xb8, 0x27, 0xeb, 0, 0, 1}))
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test file
+	file := filepath.Join(t.TempDir(), "oui.txt")
+	err = os.WriteFile(file, []byte("02-00-00   (hex)\t\tTest\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err = LoadOUIDB(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "Test"
+	got = o.Lookup(layers.NewMACEndpoint(
+		net.HardwareAddr{2, 0, 0, 0, 0, 1}))
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// test missing file
+	if _, err := LoadOUIDB(file + ".missing"); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
+
+func TestSetOUIDB(t *testing.T) {
+	o := &OUIDB{}
+	o.Add(0x00005e, 24, "Test")
+	SetOUIDB(o)
+	defer SetOUIDB(nil)
+
+	// new devices get vendor
+	var d DeviceMap
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	if got := d.Add(mac).Vendor; got != "Test" {
+		t.Errorf("got = %s; want Test", got)
+	}
+}
@@ -1,23 +1,128 @@
 package dev
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 
 	"github.com/gopacket/gopacket/layers"
 )
 
+// prefix info flags
+const (
+	prefixFlagOnLink     = 0x80
+	prefixFlagAutonomous = 0x40
+)
+
 // PrefixInfo stores a router's prefix information
 type PrefixInfo struct {
 	TimeInfo
 	Prefix layers.ICMPv6Option
 }
 
+// prefix returns the prefix in the prefix info option as string
+func (p *PrefixInfo) prefix() string {
+	pfLen := uint8(p.Prefix.Data[0])
+	pf := net.IP(p.Prefix.Data[14:])
+	return fmt.Sprintf("%v/%v", pf, pfLen)
+}
+
+// OnLink returns whether the on-link flag is set in the prefix info
+func (p *PrefixInfo) OnLink() bool {
+	return p.Prefix.Data[1]&prefixFlagOnLink != 0
+}
+
+// Autonomous returns whether the autonomous address-configuration flag is
+// set in the prefix info
+func (p *PrefixInfo) Autonomous() bool {
+	return p.Prefix.Data[1]&prefixFlagAutonomous != 0
+}
+
+// ValidLifetime returns the valid lifetime in the prefix info in seconds
+func (p *PrefixInfo) ValidLifetime() uint32 {
+	return binary.BigEndian.Uint32(p.Prefix.Data[2:6])
+}
+
+// PreferredLifetime returns the preferred lifetime in the prefix info in
+// seconds
+func (p *PrefixInfo) PreferredLifetime() uint32 {
+	return binary.BigEndian.Uint32(p.Prefix.Data[6:10])
+}
+
 // String converts the prefix to a string
 func (p *PrefixInfo) String() string {
 	prefixFmt := "Prefix: %-34s (age: %.f)"
-	pfLen := uint8(p.Prefix.Data[0])
-	pf := net.IP(p.Prefix.Data[14:])
-	ps := fmt.Sprintf("%v/%v", pf, pfLen)
-	return fmt.Sprintf(prefixFmt, ps, p.Age())
+	return fmt.Sprintf(prefixFmt, p.prefix(), p.Age())
+}
+
+// Print prints the prefix and its details to w
+func (p *PrefixInfo) Print(w io.Writer) {
+	fmt.Fprintf(w, "      %s\n", p)
+	fmt.Fprintf(w, "        Valid Lifetime: %d\n", p.ValidLifetime())
+	fmt.Fprintf(w, "        Preferred Lifetime: %d\n",
+		p.PreferredLifetime())
+	fmt.Fprintf(w, "        On-Link: %t\n", p.OnLink())
+	fmt.Fprintf(w, "        Autonomous: %t\n", p.Autonomous())
+}
+
+// MarshalJSON converts the prefix to json
+func (p *PrefixInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Prefix string `json:"prefix"`
+		TimeInfo
+		ValidLifetime     uint32 `json:"valid_lifetime"`
+		PreferredLifetime uint32 `json:"preferred_lifetime"`
+		OnLink            bool   `json:"on_link"`
+		Autonomous        bool   `json:"autonomous"`
+	}{
+		Prefix:            p.prefix(),
+		TimeInfo:          p.TimeInfo,
+		ValidLifetime:     p.ValidLifetime(),
+		PreferredLifetime: p.PreferredLifetime(),
+		OnLink:            p.OnLink(),
+		Autonomous:        p.Autonomous(),
+	})
+}
+
+// UnmarshalJSON restores the prefix from json
+func (p *PrefixInfo) UnmarshalJSON(data []byte) error {
+	var prefix struct {
+		Prefix string `json:"prefix"`
+		TimeInfo
+		ValidLifetime     uint32 `json:"valid_lifetime"`
+		PreferredLifetime uint32 `json:"preferred_lifetime"`
+		OnLink            bool   `json:"on_link"`
+		Autonomous        bool   `json:"autonomous"`
+	}
+	if err := json.Unmarshal(data, &prefix); err != nil {
+		return err
+	}
+	ip, ipnet, err := net.ParseCIDR(prefix.Prefix)
+	if err != nil || ip.To4() != nil {
+		return fmt.Errorf("invalid prefix: %s", prefix.Prefix)
+	}
+	pfLen, _ := ipnet.Mask.Size()
+
+	// rebuild prefix information option
+	opt := make([]byte, 30)
+	opt[0] = uint8(pfLen)
+	if prefix.OnLink {
+		opt[1] |= prefixFlagOnLink
+	}
+	if prefix.Autonomous {
+		opt[1] |= prefixFlagAutonomous
+	}
+	binary.BigEndian.PutUint32(opt[2:6], prefix.ValidLifetime)
+	binary.BigEndian.PutUint32(opt[6:10], prefix.PreferredLifetime)
+	copy(opt[14:], ip.To16())
+	*p = PrefixInfo{
+		TimeInfo: prefix.TimeInfo,
+		Prefix: layers.ICMPv6Option{
+			Type: layers.ICMPv6OptPrefixInfo,
+			Data: opt,
+		},
+	}
+	return nil
 }
@@ -34,3 +34,32 @@ func TestPrefixInfo(t *testing.T) {
 		t.Errorf("got = %s; want %s", got, want)
 	}
 }
+
+func TestPrefixInfoDetails(t *testing.T) {
+	p := PrefixInfo{Prefix: testICMPv6OptPrefixInfo}
+
+	// test decoded fields
+	if !p.OnLink() || !p.Autonomous() {
+		t.Errorf("got = %t, %t; want true, true", p.OnLink(),
+			p.Autonomous())
+	}
+	if p.ValidLifetime() != 2592000 || p.PreferredLifetime() != 604800 {
+		t.Errorf("got = %d, %d; want 2592000, 604800",
+			p.ValidLifetime(), p.PreferredLifetime())
+	}
+
+	// test json
+	b, err := p.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"prefix":"2001:db8:0:1::/64",` +
+		`"first_seen":"0001-01-01T00:00:00Z",` +
+		`"last_seen":"0001-01-01T00:00:00Z",` +
+		`"valid_lifetime":2592000,"preferred_lifetime":604800,` +
+		`"on_link":true,"autonomous":true}`
+	got := string(b)
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
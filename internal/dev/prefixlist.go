@@ -1,8 +1,9 @@
 package dev
 
 import (
-	"fmt"
+	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/gopacket/gopacket/layers"
 )
@@ -30,9 +31,41 @@ func (p *PrefixList) Get() []*PrefixInfo {
 	return p.Prefixes
 }
 
+// Expire removes all prefixes not seen within timeout
+func (p *PrefixList) Expire(timeout time.Duration) {
+	var prefixes []*PrefixInfo
+	for _, prefix := range p.Prefixes {
+		if prefix.IsExpired(timeout) {
+			debug("Expiring prefix")
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	p.Prefixes = prefixes
+}
+
 // Print prints all prefixes
 func (p *PrefixList) Print(w io.Writer) {
 	for _, prefix := range p.Prefixes {
-		fmt.Fprintf(w, "      %s\n", prefix)
+		prefix.Print(w)
+	}
+}
+
+// MarshalJSON converts all prefixes to a json array
+func (p *PrefixList) MarshalJSON() ([]byte, error) {
+	prefixes := p.Prefixes
+	if prefixes == nil {
+		prefixes = []*PrefixInfo{}
+	}
+	return json.Marshal(prefixes)
+}
+
+// UnmarshalJSON restores all prefixes from a json array
+func (p *PrefixList) UnmarshalJSON(data []byte) error {
+	var prefixes []*PrefixInfo
+	if err := json.Unmarshal(data, &prefixes); err != nil {
+		return err
 	}
+	p.Prefixes = prefixes
+	return nil
 }
@@ -84,7 +84,11 @@ func TestPrefixListPrint(t *testing.T) {
 	// test filled
 	p.Add(testICMPv6OptPrefixInfo)
 	p.Print(&buf)
-	want = "      Prefix: 2001:db8:0:1::/64                  (age: -1)\n"
+	want = "      Prefix: 2001:db8:0:1::/64                  (age: -1)\n" +
+		"        Valid Lifetime: 2592000\n" +
+		"        Preferred Lifetime: 604800\n" +
+		"        On-Link: true\n" +
+		"        Autonomous: true\n"
 	got = buf.String()
 	if got != want {
 		t.Errorf("got = %s; want %s", got, want)
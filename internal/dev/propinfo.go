@@ -3,23 +3,36 @@ package dev
 import (
 	"fmt"
 	"io"
+	"time"
 )
 
 // PropInfo is a device property
 type PropInfo struct {
 	TimeInfo
-	Name    string
-	Enabled bool
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	events  *eventEmitter
+}
+
+// set sets the state of the device property and emits an event if it
+// changed
+func (p *PropInfo) set(enabled bool) {
+	if p.Enabled == enabled {
+		return
+	}
+	p.Enabled = enabled
+	p.events.emit(EventPropertyChanged,
+		fmt.Sprintf("%s: %t", p.Name, enabled))
 }
 
 // Enable enables the device property
 func (p *PropInfo) Enable() {
-	p.Enabled = true
+	p.set(true)
 }
 
 // Disable disables the device property
 func (p *PropInfo) Disable() {
-	p.Enabled = false
+	p.set(false)
 }
 
 // IsEnabled checks if device property is enabled
@@ -30,6 +43,14 @@ func (p *PropInfo) IsEnabled() bool {
 	return false
 }
 
+// Expire disables the device property if it was not seen within timeout
+func (p *PropInfo) Expire(timeout time.Duration) {
+	if p.Enabled && p.IsExpired(timeout) {
+		debug("Expiring property")
+		p.Disable()
+	}
+}
+
 // Print prints the property info to w
 func (p *PropInfo) Print(w io.Writer) {
 	if !p.Enabled {
@@ -3,6 +3,7 @@ package dev
 import (
 	"bytes"
 	"testing"
+	"time"
 )
 
 func TestPropInfo(t *testing.T) {
@@ -49,3 +50,22 @@ func TestPropInfo(t *testing.T) {
 	}
 	buf.Reset()
 }
+
+func TestPropInfoExpire(t *testing.T) {
+	var p PropInfo
+
+	// test not expired
+	p.Enable()
+	p.SetTimestamp(time.Now())
+	p.Expire(time.Minute)
+	if !p.IsEnabled() {
+		t.Errorf("p.IsEnabled() = false; want true")
+	}
+
+	// test expired
+	p.SetTimestamp(time.Now().Add(-2 * time.Minute))
+	p.Expire(time.Minute)
+	if p.IsEnabled() {
+		t.Errorf("p.IsEnabled() = true; want false")
+	}
+}
@@ -0,0 +1,70 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RARoute is a route information option in a router advertisement
+type RARoute struct {
+	Prefix     string `json:"prefix"`
+	Preference string `json:"preference"`
+	Lifetime   uint32 `json:"lifetime"`
+}
+
+// String converts the route to a string
+func (r *RARoute) String() string {
+	return fmt.Sprintf("%s (preference: %s, lifetime: %d)", r.Prefix,
+		r.Preference, r.Lifetime)
+}
+
+// RAInfo stores the details of a router's router advertisements
+type RAInfo struct {
+	TimeInfo
+	Managed        bool      `json:"managed"`
+	OtherConfig    bool      `json:"other_config"`
+	Preference     string    `json:"preference"`
+	RouterLifetime uint16    `json:"router_lifetime"`
+	ReachableTime  uint32    `json:"reachable_time"`
+	RetransTimer   uint32    `json:"retrans_timer"`
+	HopLimit       uint8     `json:"hop_limit"`
+	MTU            uint32    `json:"mtu"`
+	RDNSS          []string  `json:"rdnss"`
+	RDNSSLifetime  uint32    `json:"rdnss_lifetime"`
+	DNSSL          []string  `json:"dnssl"`
+	DNSSLLifetime  uint32    `json:"dnssl_lifetime"`
+	Routes         []RARoute `json:"routes"`
+}
+
+// Print prints the router advertisement info to w
+func (r *RAInfo) Print(w io.Writer) {
+	if r == nil {
+		return
+	}
+
+	// print header fields
+	fmt.Fprintf(w, "      Managed: %t\n", r.Managed)
+	fmt.Fprintf(w, "      Other Config: %t\n", r.OtherConfig)
+	fmt.Fprintf(w, "      Preference: %s\n", r.Preference)
+	fmt.Fprintf(w, "      Router Lifetime: %d\n", r.RouterLifetime)
+	fmt.Fprintf(w, "      Reachable Time: %d\n", r.ReachableTime)
+	fmt.Fprintf(w, "      Retrans Timer: %d\n", r.RetransTimer)
+	fmt.Fprintf(w, "      Hop Limit: %d\n", r.HopLimit)
+
+	// print all options that are set
+	if r.MTU != 0 {
+		fmt.Fprintf(w, "      MTU: %d\n", r.MTU)
+	}
+	if len(r.RDNSS) > 0 {
+		fmt.Fprintf(w, "      RDNSS: %s (lifetime: %d)\n",
+			strings.Join(r.RDNSS, ", "), r.RDNSSLifetime)
+	}
+	if len(r.DNSSL) > 0 {
+		fmt.Fprintf(w, "      DNSSL: %s (lifetime: %d)\n",
+			strings.Join(r.DNSSL, ", "), r.DNSSLLifetime)
+	}
+	for i := range r.Routes {
+		fmt.Fprintf(w, "      Route: %s\n", &r.Routes[i])
+	}
+}
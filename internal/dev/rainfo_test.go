@@ -0,0 +1,57 @@
+package dev
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRAInfoPrint(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	// test nil
+	var r *RAInfo
+	r.Print(&buf)
+	if buf.String() != "" {
+		t.Errorf("got = %s; want = ", buf.String())
+	}
+
+	// test filled
+	r = &RAInfo{
+		Managed:        true,
+		Preference:     "high",
+		RouterLifetime: 1800,
+		ReachableTime:  30000,
+		RetransTimer:   1000,
+		HopLimit:       64,
+		MTU:            1500,
+		RDNSS:          []string{"2001:db8::53", "2001:db8::54"},
+		RDNSSLifetime:  600,
+		DNSSL:          []string{"example.com"},
+		DNSSLLifetime:  600,
+		Routes: []RARoute{
+			{
+				Prefix:     "2001:db8:1::/48",
+				Preference: "low",
+				Lifetime:   1800,
+			},
+		},
+	}
+	r.Print(&buf)
+	want = "      Managed: true\n" +
+		"      Other Config: false\n" +
+		"      Preference: high\n" +
+		"      Router Lifetime: 1800\n" +
+		"      Reachable Time: 30000\n" +
+		"      Retrans Timer: 1000\n" +
+		"      Hop Limit: 64\n" +
+		"      MTU: 1500\n" +
+		"      RDNSS: 2001:db8::53, 2001:db8::54 (lifetime: 600)\n" +
+		"      DNSSL: example.com (lifetime: 600)\n" +
+		"      Route: 2001:db8:1::/48 (preference: low, " +
+		"lifetime: 1800)\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gopacket/gopacket"
+)
+
+var (
+	vlanMode bool
+)
+
+// SetVLANMode enables or disables keying devices in the device table by
+// vlan and mac address; if enabled, the same mac address seen in different
+// vlans results in one device per vlan and the output is grouped by vlan
+func SetVLANMode(enable bool) {
+	vlanMode = enable
+}
+
+// deviceKey identifies a device in the device table; vlan is always 0 if
+// devices are not keyed by vlan
+type deviceKey struct {
+	vlan uint32
+	mac  gopacket.Endpoint
+}
+
+// Segment stores the device counts of a vlan
+type Segment struct {
+	VLAN        uint32 `json:"vlan"`
+	Devices     int    `json:"devices"`
+	Routers     int    `json:"routers"`
+	DHCPServers int    `json:"dhcp_servers"`
+}
+
+// String converts the segment to a string
+func (s *Segment) String() string {
+	segmentFmt := "VLAN: %-42d (devices: %d, routers: %d, " +
+		"dhcp servers: %d)"
+	return fmt.Sprintf(segmentFmt, s.VLAN, s.Devices, s.Routers,
+		s.DHCPServers)
+}
+
+// Print prints the segment header to w
+func (s *Segment) Print(w io.Writer) {
+	segmentFmt := "-----------------------------------" +
+		"-----------------------------------\n" +
+		"%s\n" +
+		"-----------------------------------" +
+		"-----------------------------------\n"
+	fmt.Fprintf(w, segmentFmt, s)
+}
+
+// getSegments returns the segments of all devices, which must be sorted by
+// vlan
+func getSegments(devices []*DeviceInfo) []*Segment {
+	var segments []*Segment
+	for _, device := range devices {
+		n := len(segments)
+		if n == 0 || segments[n-1].VLAN != device.VLAN {
+			segments = append(segments, &Segment{VLAN: device.VLAN})
+			n++
+		}
+		s := segments[n-1]
+		s.Devices++
+		if device.Router.IsEnabled() {
+			s.Routers++
+		}
+		if device.DHCP.IsEnabled() || device.DHCPv4Server != nil ||
+			device.DHCPv6Server != nil {
+			s.DHCPServers++
+		}
+	}
+	return segments
+}
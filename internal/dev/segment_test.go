@@ -0,0 +1,107 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestSegment(t *testing.T) {
+	s := Segment{VLAN: 10, Devices: 3, Routers: 1, DHCPServers: 2}
+	want := "VLAN: 10                                         " +
+		"(devices: 3, routers: 1, dhcp servers: 2)"
+	got := s.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestDeviceMapVLANMode(t *testing.T) {
+	SetVLANMode(true)
+	defer SetVLANMode(false)
+
+	// add the same mac in two vlans and a dhcp server in the first one
+	var d DeviceMap
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	server := layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})
+	d.SetVLAN(20)
+	d.Add(mac)
+	d.SetVLAN(10)
+	d.Add(mac).Router.Enable()
+	d.Add(server).DHCP.Enable()
+	if d.Get(mac) == nil || d.Get(mac).VLAN != 10 {
+		t.Errorf("got = %v; want device in vlan 10", d.Get(mac))
+	}
+	if len(d.m) != 3 {
+		t.Errorf("got = %d; want 3", len(d.m))
+	}
+
+	// check grouped output
+	var buf bytes.Buffer
+	d.Print(&buf)
+	want := "=================================================" +
+		"=====================\n" +
+		"Devices: 3                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"-------------------------------------------------" +
+		"---------------------\n" +
+		"VLAN: 10                                         " +
+		"(devices: 2, routers: 1, dhcp servers: 1)\n" +
+		"-------------------------------------------------" +
+		"---------------------\n" +
+		"MAC: 00:00:5e:00:53:01                           " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    Router: true                                 " +
+		"(age: -1)\n\n" +
+		"MAC: 00:00:5e:00:53:02                           " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    DHCP Server: true                            " +
+		"(age: -1)\n\n" +
+		"-------------------------------------------------" +
+		"---------------------\n" +
+		"VLAN: 20                                         " +
+		"(devices: 1, routers: 0, dhcp servers: 0)\n" +
+		"-------------------------------------------------" +
+		"---------------------\n" +
+		"MAC: 00:00:5e:00:53:01                           " +
+		"(age: -1, pkts: 0)\n\n"
+	got := buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+
+	// check json output and restore it
+	b, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table struct {
+		Devices []struct {
+			VLAN uint32 `json:"vlan"`
+		} `json:"devices"`
+		Segments []*Segment `json:"vlans"`
+	}
+	if err := json.Unmarshal(b, &table); err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Segments) != 2 || table.Segments[1].VLAN != 20 ||
+		table.Devices[2].VLAN != 20 {
+		t.Errorf("got = %s; want two vlans", b)
+	}
+	var r DeviceMap
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	r.SetVLAN(20)
+	if len(r.m) != 3 || r.Get(mac) == nil || r.Get(mac).VLAN != 20 {
+		t.Errorf("got = %v; want 3 devices", r.m)
+	}
+}
@@ -0,0 +1,60 @@
+package dev
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// state is the snapshot of the device table in a state file; perVLAN is
+// the vlan mode the device table was keyed with
+type state struct {
+	PerVLAN *bool           `json:"per_vlan"`
+	Devices json.RawMessage `json:"device_table"`
+}
+
+// SaveState writes a snapshot of the device table to the state file path;
+// the snapshot is written to a temporary file first and then renamed, so
+// the state file is never left half-written. Address bindings and dad
+// probes used for alerts are not saved. The caller must hold the lock
+func (d *DeviceMap) SaveState(path string) error {
+	devices, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(state{
+		PerVLAN: &vlanMode,
+		Devices: devices,
+	})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadState restores the device table from the state file path; it fails if
+// the state file was saved with a different vlan mode, because devices
+// cannot be split into vlans and merging them would lose information. The
+// caller must hold the lock
+func (d *DeviceMap) LoadState(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s.PerVLAN == nil || s.Devices == nil {
+		return fmt.Errorf("invalid state file")
+	}
+	if *s.PerVLAN != vlanMode {
+		return fmt.Errorf("state file saved with per-vlan mode %t, "+
+			"but per-vlan mode is %t", *s.PerVLAN, vlanMode)
+	}
+	return json.Unmarshal(s.Devices, d)
+}
@@ -0,0 +1,143 @@
+package dev
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+// testAlertCounter is an alert sink that counts alerts
+type testAlertCounter struct {
+	n int
+}
+
+// HandleAlert counts the new alert
+func (t *testAlertCounter) HandleAlert(alert *Alert) {
+	t.n++
+}
+
+func TestDeviceMapState(t *testing.T) {
+	var d DeviceMap
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// fill device table
+	m, _ := net.ParseMAC("00:00:5e:00:53:01")
+	mac := layers.NewMACEndpoint(m)
+	d.Packets = 3
+	device := d.Add(mac)
+	device.Packets = 3
+	device.SetTimestamp(timestamp)
+	device.Router.Enable()
+	device.Router.SetTimestamp(timestamp)
+	ra := device.AddRA()
+	ra.SetTimestamp(timestamp)
+	ra.RouterLifetime = 1800
+	ra.RDNSS = []string{"2001:db8::53"}
+	device.Prefixes.Add(testICMPv6OptPrefixInfo).SetTimestamp(timestamp)
+	lldp := device.AddLLDP()
+	lldp.SysName = "switch"
+	vlan := device.VLANs.Add(42, "VLAN")
+	vlan.Packets = 2
+	ip := layers.NewIPEndpoint(net.ParseIP("2001:db8::100"))
+	addr := device.UCasts.Add(ip)
+	addr.SetTimestamp(timestamp)
+	addr.Packets = 1
+	addr.DHCPv6 = true
+	device.MCasts.Add(layers.NewIPEndpoint(net.ParseIP("ff02::1")))
+	device.MACPeers.Add(mac)
+	d.Alerts.Add(AlertRogueRouter, mac, 42, "fe80::1", "test", timestamp)
+
+	// save and restore state
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := d.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	var r DeviceMap
+	sink := &testAlertCounter{}
+	r.Alerts.AddSink(sink)
+	if err := r.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// compare text and json output
+	var want, got bytes.Buffer
+	d.Print(&want)
+	r.Print(&got)
+	if got.String() != want.String() {
+		t.Errorf("got = %s; want = %s", got.String(), want.String())
+	}
+	want.Reset()
+	got.Reset()
+	if err := d.PrintJSON(&want); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.PrintJSON(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got = %s; want = %s", got.String(), want.String())
+	}
+
+	// restored alerts are not forwarded, repeated alerts update them
+	if sink.n != 0 {
+		t.Errorf("got = %d; want = 0", sink.n)
+	}
+	alert := r.Alerts.Add(AlertRogueRouter, mac, 42, "fe80::1", "test",
+		timestamp)
+	if sink.n != 0 || alert.Count != 2 {
+		t.Errorf("got = %d, %d; want = 0, 2", sink.n, alert.Count)
+	}
+
+	// test missing state file
+	if err := r.LoadState(path + ".missing"); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
+
+func TestDeviceMapStateVLANMode(t *testing.T) {
+	// save state in vlan mode with the same mac in two vlans
+	SetVLANMode(true)
+	defer SetVLANMode(false)
+	var d DeviceMap
+	mac := layers.NewMACEndpoint(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	d.SetVLAN(10)
+	d.Add(mac)
+	d.SetVLAN(20)
+	d.Add(mac)
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := d.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// restore in vlan mode
+	var r DeviceMap
+	if err := r.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if r.Stats().Devices != 2 {
+		t.Errorf("got = %d; want 2", r.Stats().Devices)
+	}
+
+	// restore without vlan mode should fail instead of merging devices
+	SetVLANMode(false)
+	r = DeviceMap{}
+	if err := r.LoadState(path); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+	if r.Stats().Devices != 0 {
+		t.Errorf("got = %d; want 0", r.Stats().Devices)
+	}
+
+	// invalid state file
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadState(path); err == nil {
+		t.Errorf("got = nil; want error")
+	}
+}
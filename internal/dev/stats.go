@@ -0,0 +1,80 @@
+package dev
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Stats stores statistics of the device table
+type Stats struct {
+	Packets    int            `json:"packets"`
+	Devices    int            `json:"devices"`
+	Alerts     int            `json:"alerts"`
+	Properties map[string]int `json:"properties"`
+}
+
+// Print prints the statistics to w
+func (s *Stats) Print(w io.Writer) {
+	fmt.Fprintf(w, "Packets: %d\n", s.Packets)
+	fmt.Fprintf(w, "Devices: %d\n", s.Devices)
+	fmt.Fprintf(w, "Alerts: %d\n", s.Alerts)
+	fmt.Fprintf(w, "Properties:\n")
+	for _, p := range Properties {
+		fmt.Fprintf(w, "  %s: %d\n", p, s.Properties[p])
+	}
+}
+
+// Stats returns statistics of the device table
+func (d *DeviceMap) Stats() *Stats {
+	s := &Stats{
+		Packets:    d.Packets,
+		Devices:    len(d.m),
+		Alerts:     d.Alerts.Len(),
+		Properties: make(map[string]int),
+	}
+	for _, p := range Properties {
+		s.Properties[p] = 0
+	}
+	for _, device := range d.m {
+		for _, p := range Properties {
+			if device.HasProperty(p) {
+				s.Properties[p]++
+			}
+		}
+	}
+	return s
+}
+
+// VNetPackets returns the packet counts of all vlans, vxlans and geneves in
+// the device table summed over all devices, sorted by vnet type and ID
+func (d *DeviceMap) VNetPackets() []*VNetInfo {
+	type vnetKey struct {
+		typ string
+		id  uint32
+	}
+	m := make(map[vnetKey]*VNetInfo)
+	for _, device := range d.m {
+		for _, vnets := range []*VNetMap{&device.VLANs, &device.VXLANs,
+			&device.GENEVEs} {
+			for _, vnet := range vnets.m {
+				k := vnetKey{vnet.Type, vnet.ID}
+				if m[k] == nil {
+					m[k] = &VNetInfo{Type: vnet.Type, ID: vnet.ID}
+				}
+				m[k].Packets += vnet.Packets
+			}
+		}
+	}
+	vnets := make([]*VNetInfo, 0, len(m))
+	for _, vnet := range m {
+		vnets = append(vnets, vnet)
+	}
+	sort.Slice(vnets, func(i, j int) bool {
+		if vnets[i].Type != vnets[j].Type {
+			return vnets[i].Type < vnets[j].Type
+		}
+		return vnets[i].ID < vnets[j].ID
+	})
+	return vnets
+}
@@ -0,0 +1,76 @@
+package dev
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket/layers"
+)
+
+func TestDeviceMapStats(t *testing.T) {
+	var d DeviceMap
+	d.Packets = 3
+	d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})).Router.Enable()
+	d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2})).AddLLDP()
+
+	s := d.Stats()
+	if s.Packets != 3 || s.Devices != 2 || s.Alerts != 0 ||
+		s.Properties[PropertyRouter] != 1 ||
+		s.Properties[PropertyLLDP] != 1 ||
+		s.Properties[PropertyCDP] != 0 {
+		t.Errorf("got = %+v; want 3 packets, 2 devices", s)
+	}
+
+	var buf bytes.Buffer
+	s.Print(&buf)
+	want := "Packets: 3\n" +
+		"Devices: 2\n" +
+		"Alerts: 0\n" +
+		"Properties:\n" +
+		"  bridge: 0\n" +
+		"  dhcp_server: 0\n" +
+		"  router: 1\n" +
+		"  router_advertisement: 0\n" +
+		"  powerline: 0\n" +
+		"  lldp: 1\n" +
+		"  cdp: 0\n" +
+		"  mdns: 0\n" +
+		"  dhcpv4_client: 0\n" +
+		"  dhcpv6_client: 0\n" +
+		"  dhcpv4_server: 0\n" +
+		"  dhcpv6_server: 0\n"
+	got := buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestDeviceMapVNetPackets(t *testing.T) {
+	var d DeviceMap
+	dev1 := d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	dev2 := d.Add(layers.NewMACEndpoint(
+		net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}))
+	for _, device := range []*DeviceInfo{dev1, dev2} {
+		vlan := device.VLANs.Add(10, "VLAN")
+		vlan.Packets += 2
+	}
+	vlan := dev1.VLANs.Add(5, "VLAN")
+	vlan.Packets++
+	vxlan := dev2.VXLANs.Add(10, "VXLAN")
+	vxlan.Packets += 3
+
+	want := []string{"VLAN 5: 1", "VLAN 10: 4", "VXLAN 10: 3"}
+	var got []string
+	for _, vnet := range d.VNetPackets() {
+		got = append(got, fmt.Sprintf("%s %d: %d", vnet.Type, vnet.ID,
+			vnet.Packets))
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+}
@@ -4,11 +4,15 @@ import "time"
 
 // TimeInfo stores a timestamp
 type TimeInfo struct {
-	Timestamp time.Time
+	FirstSeen time.Time `json:"first_seen"`
+	Timestamp time.Time `json:"last_seen"`
 }
 
 // SetTimestamp sets the timestamp
 func (t *TimeInfo) SetTimestamp(timestamp time.Time) {
+	if t.FirstSeen == (time.Time{}) {
+		t.FirstSeen = timestamp
+	}
 	t.Timestamp = timestamp
 }
 
@@ -17,5 +21,14 @@ func (t *TimeInfo) Age() float64 {
 	if t.Timestamp == (time.Time{}) {
 		return -1
 	}
-	return time.Since(t.Timestamp).Seconds()
+	return clock.Now().Sub(t.Timestamp).Seconds()
+}
+
+// IsExpired checks if the timestamp is older than timeout; entries without
+// a timestamp never expire
+func (t *TimeInfo) IsExpired(timeout time.Duration) bool {
+	if t.Timestamp == (time.Time{}) {
+		return false
+	}
+	return t.Age() > timeout.Seconds()
 }
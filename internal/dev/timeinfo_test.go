@@ -22,6 +22,20 @@ func TestTimeInfo(t *testing.T) {
 	if i.Timestamp != now {
 		t.Errorf("i.Timestamp = %s; want %s", i.Timestamp, now)
 	}
+	if i.FirstSeen != now {
+		t.Errorf("i.FirstSeen = %s; want %s", i.FirstSeen, now)
+	}
+
+	// test SetTimestamp() keeps first seen timestamp
+	later := now.Add(time.Second)
+	i.SetTimestamp(later)
+	if i.Timestamp != later {
+		t.Errorf("i.Timestamp = %s; want %s", i.Timestamp, later)
+	}
+	if i.FirstSeen != now {
+		t.Errorf("i.FirstSeen = %s; want %s", i.FirstSeen, now)
+	}
+	i.SetTimestamp(now)
 
 	// test Age()
 	age := i.Age()
@@ -30,3 +44,24 @@ func TestTimeInfo(t *testing.T) {
 		t.Errorf("diff = %.10f; want diff > 0 && diff < 1", diff)
 	}
 }
+
+func TestTimeInfoIsExpired(t *testing.T) {
+	var i TimeInfo
+
+	// test default, entries without timestamp never expire
+	if i.IsExpired(0) {
+		t.Errorf("i.IsExpired() = true; want false")
+	}
+
+	// test not expired
+	i.SetTimestamp(time.Now())
+	if i.IsExpired(time.Minute) {
+		t.Errorf("i.IsExpired() = true; want false")
+	}
+
+	// test expired
+	i.SetTimestamp(time.Now().Add(-2 * time.Minute))
+	if !i.IsExpired(time.Minute) {
+		t.Errorf("i.IsExpired() = false; want true")
+	}
+}
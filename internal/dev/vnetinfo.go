@@ -5,9 +5,9 @@ import "fmt"
 // VNetInfo stores virtual network information
 type VNetInfo struct {
 	TimeInfo
-	Type    string
-	ID      uint32
-	Packets int
+	Type    string `json:"type"`
+	ID      uint32 `json:"id"`
+	Packets int    `json:"packets"`
 }
 
 // String converts vnet info to a string
@@ -1,26 +1,33 @@
 package dev
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"time"
 )
 
 // VNetMap stores mappings from vnet IDs to vnet information
 type VNetMap struct {
-	m map[uint32]*VNetInfo
+	m      map[uint32]*VNetInfo
+	events *eventEmitter
 }
 
-// Add adds a vnet with id to the mapping and returns the vnet info
-func (v *VNetMap) Add(id uint32) *VNetInfo {
+// Add adds a vnet of type typ, e.g., VLAN, with id to the mapping and
+// returns the vnet info
+func (v *VNetMap) Add(id uint32, typ string) *VNetInfo {
 	if v.m == nil {
 		v.m = make(map[uint32]*VNetInfo)
 	}
 	if v.m[id] == nil {
 		debug("Adding new vnet entry")
 		vnet := VNetInfo{
-			ID: id,
+			ID:   id,
+			Type: typ,
 		}
 		v.m[id] = &vnet
+		v.events.emit(EventVNetSeen, fmt.Sprintf("%s %d", typ, id))
 	}
 	return v.m[id]
 }
@@ -38,9 +45,44 @@ func (v *VNetMap) Len() int {
 	return len(v.m)
 }
 
+// Expire removes all vnet infos not seen within timeout
+func (v *VNetMap) Expire(timeout time.Duration) {
+	for id, vnet := range v.m {
+		if vnet.IsExpired(timeout) {
+			debug("Expiring vnet entry")
+			delete(v.m, id)
+		}
+	}
+}
+
 // Print prints the vnet map to w
 func (v *VNetMap) Print(w io.Writer) {
 	for _, vnet := range v.m {
 		fmt.Fprintf(w, "    %s\n", vnet)
 	}
 }
+
+// MarshalJSON converts the vnet map to a json array sorted by vnet ID
+func (v *VNetMap) MarshalJSON() ([]byte, error) {
+	vnets := make([]*VNetInfo, 0, len(v.m))
+	for _, vnet := range v.m {
+		vnets = append(vnets, vnet)
+	}
+	sort.Slice(vnets, func(i, j int) bool {
+		return vnets[i].ID < vnets[j].ID
+	})
+	return json.Marshal(vnets)
+}
+
+// UnmarshalJSON restores the vnet map from a json array
+func (v *VNetMap) UnmarshalJSON(data []byte) error {
+	var vnets []*VNetInfo
+	if err := json.Unmarshal(data, &vnets); err != nil {
+		return err
+	}
+	v.m = make(map[uint32]*VNetInfo)
+	for _, vnet := range vnets {
+		v.m[vnet.ID] = vnet
+	}
+	return nil
+}
@@ -9,7 +9,7 @@ func TestVNetMapAdd(t *testing.T) {
 	var v VNetMap
 	var want, got *VNetInfo
 
-	want = v.Add(42)
+	want = v.Add(42, "VLAN")
 	got = v.Get(42)
 	if got != want {
 		t.Errorf("got = %p; want %p", got, want)
@@ -28,7 +28,7 @@ func TestVNetMapGet(t *testing.T) {
 	}
 
 	// test filled
-	want = v.Add(42)
+	want = v.Add(42, "VLAN")
 	got = v.Get(42)
 	if got != want {
 		t.Errorf("got = %p; want %p", got, want)
@@ -47,7 +47,7 @@ func TestVNetMapLen(t *testing.T) {
 	}
 
 	// test filled
-	v.Add(42)
+	v.Add(42, "VLAN")
 	want = 1
 	got = v.Len()
 	if got != want {
@@ -70,8 +70,7 @@ func TestVNetMapPrint(t *testing.T) {
 	buf.Reset()
 
 	// test filled
-	vnet := v.Add(32)
-	vnet.Type = "TestVNet"
+	vnet := v.Add(32, "TestVNet")
 	vnet.ID = 32
 	vnet.Packets = 128
 	v.Print(&buf)
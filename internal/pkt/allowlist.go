@@ -0,0 +1,96 @@
+package pkt
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// allowEntry is an entry in an allowlist; prefix is set if the entry is an
+// IP prefix
+type allowEntry struct {
+	addr    string
+	prefix  *net.IPNet
+	vlan    uint32
+	anyVLAN bool
+}
+
+// allowList is a list of allowed addresses, optionally restricted to vlans
+type allowList struct {
+	entries []allowEntry
+}
+
+// parseAllowEntry parses entry in the format "address[@vlan]"; address is a
+// MAC address, an IP address or an IP prefix
+func parseAllowEntry(entry string) (allowEntry, error) {
+	e := allowEntry{anyVLAN: true}
+
+	// parse optional vlan
+	addr, vlan, found := strings.Cut(entry, "@")
+	if found {
+		id, err := strconv.ParseUint(vlan, 10, 12)
+		if err != nil {
+			return e, fmt.Errorf("invalid vlan in %q", entry)
+		}
+		e.vlan = uint32(id)
+		e.anyVLAN = false
+	}
+
+	// parse address and normalize it
+	if mac, err := net.ParseMAC(addr); err == nil {
+		e.addr = mac.String()
+		return e, nil
+	}
+	if ip := net.ParseIP(addr); ip != nil {
+		e.addr = ip.String()
+		return e, nil
+	}
+	if _, prefix, err := net.ParseCIDR(addr); err == nil {
+		e.addr = prefix.String()
+		e.prefix = prefix
+		return e, nil
+	}
+	return e, fmt.Errorf("invalid address in %q", entry)
+}
+
+// newAllowList creates a new allowlist from entries
+func newAllowList(entries []string) (*allowList, error) {
+	a := &allowList{}
+	for _, entry := range entries {
+		e, err := parseAllowEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		a.entries = append(a.entries, e)
+	}
+	return a, nil
+}
+
+// matches checks if the address addr matches the entry, i.e., if it is the
+// entry's address or an IP address in the entry's prefix
+func (e *allowEntry) matches(addr string) bool {
+	if addr == e.addr {
+		return true
+	}
+	if e.prefix == nil {
+		return false
+	}
+	ip := net.ParseIP(addr)
+	return ip != nil && e.prefix.Contains(ip)
+}
+
+// allows checks if any of the addresses addrs is allowed in vlan
+func (a *allowList) allows(vlan uint32, addrs ...string) bool {
+	for _, e := range a.entries {
+		if !e.anyVLAN && e.vlan != vlan {
+			continue
+		}
+		for _, addr := range addrs {
+			if e.matches(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
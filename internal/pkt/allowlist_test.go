@@ -0,0 +1,72 @@
+package pkt
+
+import "testing"
+
+func TestNewAllowList(t *testing.T) {
+	// test valid entries
+	a, err := newAllowList([]string{
+		"00:00:5E:00:53:01",
+		"192.0.2.1@10",
+		"2001:db8::/64@20",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []allowEntry{
+		{addr: "00:00:5e:00:53:01", anyVLAN: true},
+		{addr: "192.0.2.1", vlan: 10},
+		{addr: "2001:db8::/64", vlan: 20},
+	}
+	if len(a.entries) != len(want) {
+		t.Fatalf("got = %v; want %v", a.entries, want)
+	}
+	for i := range want {
+		got := a.entries[i]
+		got.prefix = nil
+		if got != want[i] {
+			t.Errorf("got = %v; want %v", a.entries[i], want[i])
+		}
+	}
+	if a.entries[2].prefix.String() != "2001:db8::/64" {
+		t.Errorf("got = %v; want 2001:db8::/64", a.entries[2].prefix)
+	}
+
+	// test invalid entries
+	for _, entry := range []string{"invalid", "192.0.2.1@", "::1@4096"} {
+		if _, err := newAllowList([]string{entry}); err == nil {
+			t.Errorf("got = nil; want error for %s", entry)
+		}
+	}
+}
+
+func TestAllowListAllows(t *testing.T) {
+	a, err := newAllowList([]string{"00:00:5e:00:53:01", "192.0.2.1@10",
+		"198.51.100.0/24", "2001:db8::/64@30"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		vlan  uint32
+		addrs []string
+		want  bool
+	}{
+		{0, []string{"00:00:5e:00:53:01"}, true},
+		{20, []string{"00:00:5e:00:53:01"}, true},
+		{10, []string{"00:00:5e:00:53:02", "192.0.2.1"}, true},
+		{20, []string{"00:00:5e:00:53:02", "192.0.2.1"}, false},
+		{10, []string{"00:00:5e:00:53:02", "192.0.2.2"}, false},
+		{0, []string{"00:00:5e:00:53:02", "198.51.100.7"}, true},
+		{0, []string{"00:00:5e:00:53:02", "198.51.101.7"}, false},
+		{30, []string{"2001:db8::1"}, true},
+		{30, []string{"2001:db8::/64"}, true},
+		{0, []string{"2001:db8::1"}, false},
+		{30, []string{"2001:db8:1::1"}, false},
+	}
+	for _, test := range tests {
+		got := a.allows(test.vlan, test.addrs...)
+		if got != test.want {
+			t.Errorf("allows(%d, %v) = %t; want %t", test.vlan,
+				test.addrs, got, test.want)
+		}
+	}
+}
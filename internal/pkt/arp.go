@@ -1,10 +1,92 @@
 package pkt
 
 import (
+	"bytes"
+	"fmt"
+	"time"
+
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// arp spoofing detection settings
+const (
+	// claims of an ip address by different macs within this window are
+	// considered concurrent, i.e., a duplicate address
+	arpConflictWindow = 30 * time.Second
+
+	// more than arpGARPLimit gratuitous arp replies for an ip address
+	// within arpGARPWindow are considered a flood
+	arpGARPWindow = 10 * time.Second
+	arpGARPLimit  = 10
 )
 
+// isGratuitousArp checks if arp is a gratuitous arp packet
+func isGratuitousArp(arp *layers.ARP) bool {
+	return bytes.Equal(arp.SourceProtAddress, arp.DstProtAddress)
+}
+
+// checkArp updates the ip to mac binding of the sender of arp in packet and
+// raises alerts if the ip moved to another mac, if several macs claim the
+// ip concurrently or if the sender floods gratuitous arp replies
+func checkArp(packet gopacket.Packet, arp *layers.ARP) {
+	linkSrc := layers.NewMACEndpoint(arp.SourceHwAddress)
+	netSrc := layers.NewIPEndpoint(arp.SourceProtAddress)
+	vlan := getVlan(packet)
+	b := devices.Bindings.Add(vlan, netSrc)
+	if b == nil {
+		return
+	}
+	timestamp := packet.Metadata().Timestamp
+
+	// check if ip is claimed by another mac
+	if b.MAC != (gopacket.Endpoint{}) && b.MAC != linkSrc {
+		duplicate := false
+		for mac, claim := range b.Claims {
+			if mac == linkSrc ||
+				timestamp.Sub(claim.Timestamp) > arpConflictWindow {
+				continue
+			}
+			debug("Duplicate IP")
+			duplicate = true
+			devices.Alerts.Add(dev.AlertDuplicateIP, linkSrc, vlan,
+				netSrc.String(), fmt.Sprintf("ip %s claimed by %s and %s", netSrc,
+					mac, linkSrc), timestamp)
+		}
+		if !duplicate {
+			debug("IP Moved")
+			devices.Alerts.Add(dev.AlertIPMoved, linkSrc, vlan,
+				netSrc.String(), fmt.Sprintf("ip %s moved from %s to %s", netSrc,
+					b.MAC, linkSrc), timestamp)
+		}
+	}
+
+	// update binding
+	b.MAC = linkSrc
+	b.SetTimestamp(timestamp)
+	c := b.Claim(linkSrc)
+	c.SetTimestamp(timestamp)
+
+	// check gratuitous arp replies
+	if arp.Operation != layers.ARPReply || !isGratuitousArp(arp) {
+		return
+	}
+	if c.GARPStart.IsZero() || timestamp.Sub(c.GARPStart) > arpGARPWindow {
+		c.GARPStart = timestamp
+		c.GARPs = 0
+	}
+	c.GARPs++
+	if c.GARPs > arpGARPLimit {
+		debug("Gratuitous ARP Flood")
+		devices.Alerts.Add(dev.AlertGARPFlood, linkSrc, vlan,
+			netSrc.String(), fmt.Sprintf("%d gratuitous arp replies for ip %s "+
+				"within %s", c.GARPs, netSrc, arpGARPWindow),
+			timestamp)
+	}
+}
+
 // parseArp parses ARP packets
 func parseArp(packet gopacket.Packet) {
 	arpLayer := packet.Layer(layers.LayerTypeARP)
@@ -24,6 +106,9 @@ func parseArp(packet gopacket.Packet) {
 
 		// add to table
 		dev := devices.Add(linkSrc)
-		dev.UCasts.Add(netSrc)
+		setAddrTimestamp(dev.UCasts.Add(netSrc), packet)
+
+		// check for arp spoofing and ip conflicts
+		checkArp(packet, arp)
 	}
 }
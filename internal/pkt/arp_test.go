@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"log"
 	"net"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
@@ -62,7 +64,7 @@ func TestParseArp(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: 127.0.0.1                                " +
@@ -72,3 +74,203 @@ func TestParseArp(t *testing.T) {
 		t.Errorf("got = %s; want = %s", got, want)
 	}
 }
+
+func testParseArpCreatePacket(mac net.HardwareAddr, src, dst net.IP,
+	op uint16, timestamp time.Time) gopacket.Packet {
+	return testParseArpCreateVlanPacket(0, mac, src, dst, op, timestamp)
+}
+
+// testParseArpCreateVlanPacket creates an arp packet in vlan, the packet is
+// untagged if vlan is 0
+func testParseArpCreateVlanPacket(vlan uint16, mac net.HardwareAddr, src,
+	dst net.IP, op uint16, timestamp time.Time) gopacket.Packet {
+	// prepare creation of packet
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	pktBuf := gopacket.NewSerializeBuffer()
+
+	// create arp header
+	ethLayer := &layers.Ethernet{
+		SrcMAC:       mac,
+		DstMAC:       net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		EthernetType: layers.EthernetTypeARP,
+	}
+	arpLayer := &layers.ARP{
+		AddrType:          layers.LinkTypeEthernet,
+		Protocol:          layers.EthernetTypeIPv4,
+		HwAddressSize:     6,
+		ProtAddressSize:   4,
+		Operation:         op,
+		SourceHwAddress:   mac,
+		SourceProtAddress: src.To4(),
+		DstHwAddress:      []byte{0, 0, 0, 0, 0, 0},
+		DstProtAddress:    dst.To4(),
+	}
+
+	// serialize to buffer
+	var err error
+	if vlan == 0 {
+		err = gopacket.SerializeLayers(pktBuf, opts, ethLayer, arpLayer)
+	} else {
+		ethLayer.EthernetType = layers.EthernetTypeDot1Q
+		vlanLayer := &layers.Dot1Q{
+			VLANIdentifier: vlan,
+			Type:           layers.EthernetTypeARP,
+		}
+		err = gopacket.SerializeLayers(pktBuf, opts, ethLayer,
+			vlanLayer, arpLayer)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// create packet from buffer
+	pkt := gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+	pkt.Metadata().Timestamp = timestamp
+	return pkt
+}
+
+// testAlertSink is an alert sink that stores all alerts it receives
+type testAlertSink struct {
+	alerts []*dev.Alert
+}
+
+// HandleAlert stores the alert
+func (s *testAlertSink) HandleAlert(alert *dev.Alert) {
+	s.alerts = append(s.alerts, alert)
+}
+
+func TestParseArpSpoofing(t *testing.T) {
+	var want, got []string
+
+	// getAlerts returns the types and messages of all alerts
+	getAlerts := func() []string {
+		var alerts []string
+		for _, a := range devices.Alerts.Get() {
+			alerts = append(alerts, a.Type+": "+a.Message)
+		}
+		return alerts
+	}
+
+	// set device table
+	devices = &dev.DeviceMap{}
+	mac1 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}
+	mac2 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}
+	ip := net.IP{192, 0, 2, 1}
+	timestamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// test ip probe, no binding
+	parseArp(testParseArpCreatePacket(mac1, net.IPv4zero, ip,
+		layers.ARPRequest, timestamp))
+	if devices.Bindings.Len() != 0 {
+		t.Errorf("got = %d; want 0", devices.Bindings.Len())
+	}
+
+	// test ip moving to other mac
+	parseArp(testParseArpCreatePacket(mac1, ip, net.IP{192, 0, 2, 2},
+		layers.ARPRequest, timestamp))
+	parseArp(testParseArpCreatePacket(mac2, ip, net.IP{192, 0, 2, 2},
+		layers.ARPRequest, timestamp.Add(time.Minute)))
+	want = []string{
+		"ip-moved: ip 192.0.2.1 moved from 00:00:5e:00:53:01 to " +
+			"00:00:5e:00:53:02",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+
+	// test concurrent claims
+	devices = &dev.DeviceMap{}
+	parseArp(testParseArpCreatePacket(mac1, ip, net.IP{192, 0, 2, 2},
+		layers.ARPRequest, timestamp))
+	parseArp(testParseArpCreatePacket(mac2, ip, net.IP{192, 0, 2, 2},
+		layers.ARPReply, timestamp.Add(time.Second)))
+	want = []string{
+		"duplicate-ip: ip 192.0.2.1 claimed by 00:00:5e:00:53:01 and " +
+			"00:00:5e:00:53:02",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+	b := devices.Bindings.Get(0, layers.NewIPEndpoint(ip))
+	if b.MAC != layers.NewMACEndpoint(mac2) || len(b.Claims) != 2 {
+		t.Errorf("got = %s, %d; want = %s, 2", b.MAC, len(b.Claims),
+			mac2)
+	}
+
+	// test two ips claimed by one mac, one alert per ip
+	devices = &dev.DeviceMap{}
+	sink := &testAlertSink{}
+	devices.Alerts.AddSink(sink)
+	ip2 := net.IP{192, 0, 2, 3}
+	parseArp(testParseArpCreatePacket(mac1, ip, net.IP{192, 0, 2, 2},
+		layers.ARPRequest, timestamp))
+	parseArp(testParseArpCreatePacket(mac1, ip2, net.IP{192, 0, 2, 2},
+		layers.ARPRequest, timestamp))
+	parseArp(testParseArpCreatePacket(mac2, ip, net.IP{192, 0, 2, 2},
+		layers.ARPReply, timestamp.Add(time.Second)))
+	parseArp(testParseArpCreatePacket(mac2, ip2, net.IP{192, 0, 2, 2},
+		layers.ARPReply, timestamp.Add(time.Second)))
+	want = []string{
+		"duplicate-ip: ip 192.0.2.1 claimed by 00:00:5e:00:53:01 and " +
+			"00:00:5e:00:53:02",
+		"duplicate-ip: ip 192.0.2.3 claimed by 00:00:5e:00:53:01 and " +
+			"00:00:5e:00:53:02",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+	alerts := devices.Alerts.Get()
+	if len(sink.alerts) != 2 || alerts[0].Count != 1 ||
+		alerts[1].Count != 1 {
+		t.Errorf("got = %d, %d, %d; want = 2, 1, 1", len(sink.alerts),
+			alerts[0].Count, alerts[1].Count)
+	}
+
+	// test same ip in different vlans, no alerts
+	devices = &dev.DeviceMap{}
+	parseArp(testParseArpCreateVlanPacket(10, mac1, ip,
+		net.IP{192, 0, 2, 2}, layers.ARPRequest, timestamp))
+	parseArp(testParseArpCreateVlanPacket(20, mac2, ip,
+		net.IP{192, 0, 2, 2}, layers.ARPRequest,
+		timestamp.Add(time.Second)))
+	if got = getAlerts(); len(got) != 0 {
+		t.Errorf("got = %v; want no alerts", got)
+	}
+	if devices.Bindings.Len() != 2 {
+		t.Errorf("got = %d; want 2", devices.Bindings.Len())
+	}
+
+	// test gratuitous arp reply flood
+	devices = &dev.DeviceMap{}
+	for i := 0; i <= arpGARPLimit; i++ {
+		parseArp(testParseArpCreatePacket(mac1, ip, ip,
+			layers.ARPReply, timestamp.Add(
+				time.Duration(i)*time.Millisecond)))
+	}
+	want = []string{
+		"garp-flood: 11 gratuitous arp replies for ip 192.0.2.1 " +
+			"within 10s",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+
+	// test gratuitous arp replies outside window
+	devices = &dev.DeviceMap{}
+	for i := 0; i <= arpGARPLimit; i++ {
+		parseArp(testParseArpCreatePacket(mac1, ip, ip,
+			layers.ARPReply, timestamp.Add(
+				time.Duration(2*i)*time.Second)))
+	}
+	if devices.Alerts.Len() != 0 {
+		t.Errorf("got = %v; want = []", getAlerts())
+	}
+}
@@ -0,0 +1,99 @@
+package pkt
+
+import (
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// cdpCapabilities converts the cdp capabilities to a list of strings
+func cdpCapabilities(caps layers.CDPCapabilities) []string {
+	var c []string
+	for _, capability := range []struct {
+		enabled bool
+		name    string
+	}{
+		{caps.L3Router, "router"},
+		{caps.TBBridge, "trans-bridge"},
+		{caps.SPBridge, "source-route-bridge"},
+		{caps.L2Switch, "switch"},
+		{caps.IsHost, "host"},
+		{caps.IGMPFilter, "igmp"},
+		{caps.L1Repeater, "repeater"},
+		{caps.IsPhone, "phone"},
+		{caps.RemotelyManaged, "remote"},
+	} {
+		if capability.enabled {
+			c = append(c, capability.name)
+		}
+	}
+	return c
+}
+
+// cdpDuplex gets the duplex setting from the cdp tlvs
+func cdpDuplex(cdp *layers.CiscoDiscovery,
+	info *layers.CiscoDiscoveryInfo) string {
+	for _, v := range cdp.Values {
+		if v.Type != layers.CDPTLVFullDuplex {
+			continue
+		}
+		if info.FullDuplex {
+			return "full"
+		}
+		return "half"
+	}
+	return ""
+}
+
+// parseCdpInfo parses the cdp info layer and stores it in c
+func parseCdpInfo(cdp *layers.CiscoDiscovery,
+	info *layers.CiscoDiscoveryInfo, c *dev.CDPInfo) {
+	c.DeviceID = info.DeviceID
+	c.Platform = info.Platform
+	c.Version = info.Version
+	c.PortID = info.PortID
+	c.NativeVLAN = info.NativeVLAN
+	c.VoiceVLAN = info.VLANReply.VLAN
+	c.Duplex = cdpDuplex(cdp, info)
+	c.Capabilities = cdpCapabilities(info.Capabilities)
+	for _, addr := range info.Addresses {
+		c.Addresses = append(c.Addresses, addr.String())
+	}
+}
+
+// parseCdp parses cdp (cisco discovery protocol) packets
+func parseCdp(packet gopacket.Packet) {
+	cdpLayer := packet.Layer(layers.LayerTypeCiscoDiscovery)
+	if cdpLayer == nil {
+		return
+	}
+	infoLayer := packet.Layer(layers.LayerTypeCiscoDiscoveryInfo)
+	if infoLayer == nil {
+		return
+	}
+	debug("CDP packet")
+	cdp, _ := cdpLayer.(*layers.CiscoDiscovery)
+	info, _ := infoLayer.(*layers.CiscoDiscoveryInfo)
+	linkSrc, _ := getMacs(packet)
+	timestamp := packet.Metadata().Timestamp
+
+	// add device and replace its cdp info with the advertised one
+	device := devices.Add(linkSrc)
+	c := device.AddCDP()
+	*c = dev.CDPInfo{TimeInfo: c.TimeInfo}
+	c.SetTimestamp(timestamp)
+	parseCdpInfo(cdp, info, c)
+
+	// mark routers and bridges
+	if info.Capabilities.L3Router {
+		device.Router.Enable()
+		device.Router.SetTimestamp(timestamp)
+	}
+	if info.Capabilities.TBBridge ||
+		info.Capabilities.SPBridge ||
+		info.Capabilities.L2Switch {
+		device.Bridge.Enable()
+		device.Bridge.SetTimestamp(timestamp)
+	}
+}
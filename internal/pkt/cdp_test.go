@@ -0,0 +1,121 @@
+package pkt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// testCDPValue creates a cdp tlv with type t and value v
+func testCDPValue(t layers.CDPTLVType, v []byte) []byte {
+	tlv := make([]byte, 4, 4+len(v))
+	binary.BigEndian.PutUint16(tlv[0:2], uint16(t))
+	binary.BigEndian.PutUint16(tlv[2:4], uint16(4+len(v)))
+	return append(tlv, v...)
+}
+
+func testParseCDPCreatePacket() gopacket.Packet {
+	// prepare creation of packet
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	pktBuf := gopacket.NewSerializeBuffer()
+
+	// create cdp header: version 2, ttl 180, checksum
+	cdp := []byte{2, 180, 0, 0}
+	for _, tlv := range [][]byte{
+		testCDPValue(layers.CDPTLVDevID, []byte("switch1")),
+		// one ipv4 address: 192.0.2.1
+		testCDPValue(layers.CDPTLVAddress, []byte{
+			0, 0, 0, 1, 1, 1, 0xcc, 0, 4, 192, 0, 2, 1}),
+		testCDPValue(layers.CDPTLVPortID, []byte("FastEthernet0/1")),
+		// capabilities: router, switch
+		testCDPValue(layers.CDPTLVCapabilities, []byte{0, 0, 0, 0x09}),
+		testCDPValue(layers.CDPTLVVersion, []byte("IOS 12.2")),
+		testCDPValue(layers.CDPTLVPlatform, []byte("cisco WS-C2960")),
+		testCDPValue(layers.CDPTLVNativeVLAN, []byte{0, 1}),
+		testCDPValue(layers.CDPTLVFullDuplex, []byte{1}),
+		testCDPValue(layers.CDPTLVVLANReply, []byte{1, 0, 10}),
+	} {
+		cdp = append(cdp, tlv...)
+	}
+
+	// create headers
+	ethLayer := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{0x1, 0x0, 0x0c, 0xcc, 0xcc, 0xcc},
+		EthernetType: layers.EthernetTypeLLC,
+		Length:       uint16(8 + len(cdp)),
+	}
+	llcLayer := &layers.LLC{
+		DSAP:    0xaa,
+		SSAP:    0xaa,
+		Control: 0x3,
+	}
+	snapLayer := &layers.SNAP{
+		OrganizationalCode: []byte{0x00, 0x00, 0x0c},
+		Type:               layers.EthernetTypeCiscoDiscovery,
+	}
+	cdpLayer := gopacket.Payload(cdp)
+
+	// serialize to buffer
+	err := gopacket.SerializeLayers(pktBuf, opts,
+		ethLayer, llcLayer, snapLayer, cdpLayer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// create packet from buffer
+	pkt := gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+	return pkt
+}
+
+func TestParseCDP(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	parseCdp(testParseCDPCreatePacket())
+
+	// check output
+	devices.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    Bridge: true                                 " +
+		"(age: -1)\n" +
+		"    Router: true                                 " +
+		"(age: -1)\n" +
+		"    CDP: true                                    " +
+		"(age: -1)\n" +
+		"      Device ID: switch1\n" +
+		"      Platform: cisco WS-C2960\n" +
+		"      Software Version: IOS 12.2\n" +
+		"      Port ID: FastEthernet0/1\n" +
+		"      Native VLAN: 1\n" +
+		"      Voice VLAN: 10\n" +
+		"      Duplex: full\n" +
+		"      Capabilities: router, switch\n" +
+		"      Address: 192.0.2.1\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
@@ -1,10 +1,306 @@
 package pkt
 
 import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// dhcp constants not defined in gopacket
+const (
+	dhcpv4OptFQDN   layers.DHCPOpt = 81
+	dhcpv4FQDNFlagE                = 0x04
 )
 
+// dhcpHexString converts data in a dhcp option to a hex string
+func dhcpHexString(data []byte) string {
+	var s []string
+	for _, b := range data {
+		s = append(s, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(s, ":")
+}
+
+// dhcpFingerprint converts the requested options in data to a fingerprint
+func dhcpFingerprint(data []byte) string {
+	var s []string
+	for _, b := range data {
+		s = append(s, strconv.Itoa(int(b)))
+	}
+	return strings.Join(s, ",")
+}
+
+// dhcpv4FQDN gets the domain name from the dhcpv4 client fqdn option in data
+func dhcpv4FQDN(data []byte) string {
+	// flags, rcode1, rcode2, domain name
+	if len(data) < 4 {
+		return ""
+	}
+	if data[0]&dhcpv4FQDNFlagE != 0 {
+		// canonical wire format encoding
+		names := getDomainNames(data[3:])
+		if len(names) == 0 {
+			return ""
+		}
+		return names[0]
+	}
+	return string(data[3:])
+}
+
+// parseDhcpv4Client parses the options in a dhcpv4 client request and stores
+// them in the client info c
+func parseDhcpv4Client(dhcp *layers.DHCPv4, c *dev.DHCPClientInfo) {
+	for _, o := range dhcp.Options {
+		switch o.Type {
+		case layers.DHCPOptHostname:
+			c.Hostname = string(o.Data)
+		case layers.DHCPOptClassID:
+			c.VendorClass = string(o.Data)
+		case layers.DHCPOptClientID:
+			c.ClientID = dhcpHexString(o.Data)
+		case layers.DHCPOptParamsRequest:
+			c.Fingerprint = dhcpFingerprint(o.Data)
+		case dhcpv4OptFQDN:
+			c.FQDN = dhcpv4FQDN(o.Data)
+		}
+	}
+}
+
+// dhcpv6VendorClass converts the dhcpv6 vendor class option in data to a
+// string
+func dhcpv6VendorClass(data []byte) string {
+	// enterprise number, list of length and vendor class data
+	if len(data) < 4 {
+		return ""
+	}
+	enterprise := binary.BigEndian.Uint32(data[0:4])
+	data = data[4:]
+	var classes []string
+	for len(data) >= 2 {
+		l := int(binary.BigEndian.Uint16(data[0:2]))
+		data = data[2:]
+		if l > len(data) {
+			break
+		}
+		classes = append(classes, string(data[:l]))
+		data = data[l:]
+	}
+	return fmt.Sprintf("%d: %s", enterprise, strings.Join(classes, ", "))
+}
+
+// dhcpv6Fingerprint converts the requested options in the dhcpv6 option
+// request option in data to a fingerprint
+func dhcpv6Fingerprint(data []byte) string {
+	var s []string
+	for len(data) >= 2 {
+		opt := binary.BigEndian.Uint16(data[0:2])
+		s = append(s, strconv.Itoa(int(opt)))
+		data = data[2:]
+	}
+	return strings.Join(s, ",")
+}
+
+// dhcpv6FQDN gets the domain name from the dhcpv6 client fqdn option in data
+func dhcpv6FQDN(data []byte) string {
+	// flags, domain name
+	if len(data) < 2 {
+		return ""
+	}
+	names := getDomainNames(data[1:])
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// parseDhcpv6Client parses the options in a dhcpv6 client message and stores
+// them in the client info c
+func parseDhcpv6Client(dhcp *layers.DHCPv6, c *dev.DHCPClientInfo) {
+	for _, o := range dhcp.Options {
+		switch o.Code {
+		case layers.DHCPv6OptClientID:
+			c.ClientID = dhcpHexString(o.Data)
+		case layers.DHCPv6OptVendorClass:
+			c.VendorClass = dhcpv6VendorClass(o.Data)
+		case layers.DHCPv6OptOro:
+			c.Fingerprint = dhcpv6Fingerprint(o.Data)
+		case layers.DHCPv6OptClientFQDN:
+			c.FQDN = dhcpv6FQDN(o.Data)
+		}
+	}
+}
+
+// dhcpIPs converts the list of ip addresses with length l in data to strings
+func dhcpIPs(data []byte, l int) []string {
+	var ips []string
+	for len(data) >= l {
+		ips = append(ips, net.IP(data[:l]).String())
+		data = data[l:]
+	}
+	return ips
+}
+
+// dhcpv4MsgType gets the message type of the dhcpv4 packet
+func dhcpv4MsgType(dhcp *layers.DHCPv4) layers.DHCPMsgType {
+	for _, o := range dhcp.Options {
+		if o.Type == layers.DHCPOptMessageType && len(o.Data) == 1 {
+			return layers.DHCPMsgType(o.Data[0])
+		}
+	}
+	return layers.DHCPMsgTypeUnspecified
+}
+
+// parseDhcpv4Server parses the configuration in a dhcpv4 offer or ack and
+// stores it in the server info s
+func parseDhcpv4Server(dhcp *layers.DHCPv4, s *dev.DHCPServerInfo) {
+	if !dhcp.YourClientIP.IsUnspecified() {
+		s.Address = dhcp.YourClientIP.String()
+	}
+	for _, o := range dhcp.Options {
+		switch o.Type {
+		case layers.DHCPOptServerID:
+			if len(o.Data) == 4 {
+				s.ServerID = net.IP(o.Data).String()
+			}
+		case layers.DHCPOptSubnetMask:
+			if len(o.Data) == 4 {
+				s.SubnetMask = net.IP(o.Data).String()
+			}
+		case layers.DHCPOptRouter:
+			s.Routers = dhcpIPs(o.Data, net.IPv4len)
+		case layers.DHCPOptDNS:
+			s.DNSServers = dhcpIPs(o.Data, net.IPv4len)
+		case layers.DHCPOptDomainName:
+			s.DomainName = string(o.Data)
+		case layers.DHCPOptDomainSearch:
+			s.DomainSearch = getDomainNames(o.Data)
+		case layers.DHCPOptLeaseTime:
+			if len(o.Data) == 4 {
+				s.LeaseTime = binary.BigEndian.Uint32(o.Data)
+			}
+		}
+	}
+}
+
+// dhcpv6IAAddr returns the address and valid lifetime in the dhcpv6
+// identity association for non-temporary addresses option in data; the
+// address is nil if there is no ia address option
+func dhcpv6IAAddr(data []byte) (net.IP, uint32) {
+	// iaid, t1, t2, ia_na options
+	if len(data) < 12 {
+		return nil, 0
+	}
+	data = data[12:]
+	for len(data) >= 4 {
+		code := layers.DHCPv6Opt(binary.BigEndian.Uint16(data[0:2]))
+		l := int(binary.BigEndian.Uint16(data[2:4]))
+		data = data[4:]
+		if l > len(data) {
+			return nil, 0
+		}
+		// ia address option: address, preferred and valid lifetime
+		if code == layers.DHCPv6OptIAAddr && l >= 24 {
+			return net.IP(data[0:16]), binary.BigEndian.Uint32(
+				data[20:24])
+		}
+		data = data[l:]
+	}
+	return nil, 0
+}
+
+// parseDhcpv6IANA parses the dhcpv6 identity association for non-temporary
+// addresses option in data and stores the offered address in s
+func parseDhcpv6IANA(data []byte, s *dev.DHCPServerInfo) {
+	if ip, lifetime := dhcpv6IAAddr(data); ip != nil {
+		s.Address = ip.String()
+		s.LeaseTime = lifetime
+	}
+}
+
+// parseDhcpv6Server parses the configuration in a dhcpv6 advertise or reply
+// and stores it in the server info s
+func parseDhcpv6Server(dhcp *layers.DHCPv6, s *dev.DHCPServerInfo) {
+	for _, o := range dhcp.Options {
+		switch o.Code {
+		case layers.DHCPv6OptServerID:
+			s.ServerID = dhcpHexString(o.Data)
+		case layers.DHCPv6OptIANA:
+			parseDhcpv6IANA(o.Data, s)
+		case layers.DHCPv6OptDNSServers:
+			s.DNSServers = dhcpIPs(o.Data, net.IPv6len)
+		case layers.DHCPv6OptDomainList:
+			s.DomainSearch = getDomainNames(o.Data)
+		}
+	}
+}
+
+// dhcpv6IsClientMsg checks if the dhcpv6 message type is sent by clients
+func dhcpv6IsClientMsg(msgType layers.DHCPv6MsgType) bool {
+	switch msgType {
+	case layers.DHCPv6MsgTypeSolicit,
+		layers.DHCPv6MsgTypeRequest,
+		layers.DHCPv6MsgTypeConfirm,
+		layers.DHCPv6MsgTypeRenew,
+		layers.DHCPv6MsgTypeRebind,
+		layers.DHCPv6MsgTypeRelease,
+		layers.DHCPv6MsgTypeDecline,
+		layers.DHCPv6MsgTypeInformationRequest:
+		return true
+	}
+	return false
+}
+
+// markDhcpv6Address marks the address assigned in the ia_na option of the
+// dhcpv6 reply packet as dhcpv6 assigned address of the client
+func markDhcpv6Address(packet gopacket.Packet, dhcp *layers.DHCPv6) {
+	var ip net.IP
+	for _, o := range dhcp.Options {
+		if o.Code == layers.DHCPv6OptIANA {
+			ip, _ = dhcpv6IAAddr(o.Data)
+			break
+		}
+	}
+	if ip == nil {
+		return
+	}
+	_, linkDst := getMacs(packet)
+	if ip.IsMulticast() || len(linkDst.Raw()) == 0 ||
+		linkDst.Raw()[0]&0x01 != 0 {
+		return
+	}
+	a := devices.Add(linkDst).UCasts.Add(layers.NewIPEndpoint(ip))
+	if a == nil {
+		return
+	}
+	a.SetTimestamp(packet.Metadata().Timestamp)
+	a.SetDHCPv6()
+}
+
+// checkDhcpServer raises an alert if the sender of the dhcp server message
+// packet is not an authorized dhcp server
+func checkDhcpServer(packet gopacket.Packet, msg string) {
+	if dhcpServers == nil {
+		return
+	}
+	linkSrc, _ := getMacs(packet)
+	netSrc, _ := getIps(packet)
+	vlan := getVlan(packet)
+	if dhcpServers.allows(vlan, linkSrc.String(), netSrc.String()) {
+		return
+	}
+	debug("Rogue DHCP Server")
+	devices.Alerts.Add(dev.AlertRogueDHCPServer, linkSrc, vlan, "",
+		fmt.Sprintf("unauthorized dhcp server %s sent %s", netSrc, msg),
+		packet.Metadata().Timestamp)
+}
+
 // parseDhcp parses dhcp packets
 func parseDhcp(packet gopacket.Packet) {
 	// DHCP v4
@@ -17,13 +313,29 @@ func parseDhcp(packet gopacket.Packet) {
 		dev := devices.Add(linkSrc)
 		if dhcp.Operation == layers.DHCPOpRequest {
 			debug("DHCP Request")
+			c := dev.AddDHCPv4Client()
+			c.SetTimestamp(packet.Metadata().Timestamp)
+			parseDhcpv4Client(dhcp, c)
+			devices.UpdateCorrelation(dev)
 			return
 		}
 		if dhcp.Operation == layers.DHCPOpReply {
 			debug("DHCP Reply")
 			// mark this device as dhcp server
+			timestamp := packet.Metadata().Timestamp
 			dev.DHCP.Enable()
-			dev.DHCP.SetTimestamp(packet.Metadata().Timestamp)
+			dev.DHCP.SetTimestamp(timestamp)
+
+			// parse offered configuration
+			switch dhcpv4MsgType(dhcp) {
+			case layers.DHCPMsgTypeOffer, layers.DHCPMsgTypeAck:
+				checkDhcpServer(packet, "dhcpv4 "+
+					strings.ToLower(dhcpv4MsgType(dhcp).String()))
+				s := dev.AddDHCPv4Server()
+				s.Reset()
+				s.SetTimestamp(timestamp)
+				parseDhcpv4Server(dhcp, s)
+			}
 		}
 	}
 
@@ -43,11 +355,11 @@ func parseDhcp(packet gopacket.Packet) {
 			debug("DHCPv6 Solicit")
 		case layers.DHCPv6MsgTypeAdvertise:
 			debug("DHCPv6 Advertise")
-		case layers.DHCPv6MsgTypeRequest:
-			debug("DHCPv6 Request")
 			// server
 			dev.DHCP.Enable()
 			dev.DHCP.SetTimestamp(timestamp)
+		case layers.DHCPv6MsgTypeRequest:
+			debug("DHCPv6 Request")
 		case layers.DHCPv6MsgTypeConfirm:
 			debug("DHCPv6 Confirm")
 		case layers.DHCPv6MsgTypeRenew:
@@ -78,5 +390,27 @@ func parseDhcp(packet gopacket.Packet) {
 			dev.DHCP.Enable()
 			dev.DHCP.SetTimestamp(timestamp)
 		}
+
+		// parse client information
+		if dhcpv6IsClientMsg(dhcp.MsgType) {
+			c := dev.AddDHCPv6Client()
+			c.SetTimestamp(timestamp)
+			parseDhcpv6Client(dhcp, c)
+			devices.UpdateCorrelation(dev)
+		}
+
+		// parse offered configuration
+		switch dhcp.MsgType {
+		case layers.DHCPv6MsgTypeAdvertise, layers.DHCPv6MsgTypeReply:
+			checkDhcpServer(packet, "dhcpv6 "+
+				strings.ToLower(dhcp.MsgType.String()))
+			s := dev.AddDHCPv6Server()
+			s.Reset()
+			s.SetTimestamp(timestamp)
+			parseDhcpv6Server(dhcp, s)
+			if dhcp.MsgType == layers.DHCPv6MsgTypeReply {
+				markDhcpv6Address(packet, dhcp)
+			}
+		}
 	}
 }
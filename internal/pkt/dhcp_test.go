@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"log"
 	"net"
+	"reflect"
 	"testing"
 
 	"github.com/gopacket/gopacket"
@@ -78,10 +79,12 @@ func TestParseDHCPv4(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    DHCP Server: true                            " +
+		"(age: -1)\n" +
+		"    DHCPv4 Client: true                          " +
 		"(age: -1)\n\n"
 	got = buf.String()
 	if got != want {
@@ -167,11 +170,15 @@ func TestParseDHCPv6(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    DHCP Server: true                            " +
 		"(age: -1)\n" +
+		"    DHCPv6 Server: true                          " +
+		"(age: -1)\n" +
+		"    DHCPv6 Client: true                          " +
+		"(age: -1)\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: ::1                                      " +
 		"(age: -1, pkts: 0)\n\n"
@@ -180,3 +187,462 @@ func TestParseDHCPv6(t *testing.T) {
 		t.Errorf("got = %s; want = %s", got, want)
 	}
 }
+
+func testParseDHCPCreatePacket(src, dst net.IP, srcPort, dstPort uint16,
+	dhcpLayer gopacket.SerializableLayer) gopacket.Packet {
+	// prepare creation of packet
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	pktBuf := gopacket.NewSerializeBuffer()
+
+	// create headers
+	ethLayer := &layers.Ethernet{
+		SrcMAC: net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC: net.HardwareAddr{6, 5, 4, 3, 2, 1},
+	}
+	var ipLayer gopacket.SerializableLayer
+	udpLayer := &layers.UDP{
+		SrcPort: layers.UDPPort(srcPort),
+		DstPort: layers.UDPPort(dstPort),
+	}
+	if src.To4() != nil {
+		ethLayer.EthernetType = layers.EthernetTypeIPv4
+		ip := &layers.IPv4{
+			Version:  4,
+			SrcIP:    src,
+			DstIP:    dst,
+			Protocol: layers.IPProtocolUDP,
+		}
+		udpLayer.SetNetworkLayerForChecksum(ip)
+		ipLayer = ip
+	} else {
+		ethLayer.EthernetType = layers.EthernetTypeIPv6
+		ip := &layers.IPv6{
+			Version:    6,
+			SrcIP:      src,
+			DstIP:      dst,
+			NextHeader: layers.IPProtocolUDP,
+		}
+		udpLayer.SetNetworkLayerForChecksum(ip)
+		ipLayer = ip
+	}
+
+	// serialize to buffer
+	err := gopacket.SerializeLayers(pktBuf, opts,
+		ethLayer, ipLayer, udpLayer, dhcpLayer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// create packet from buffer
+	return gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+}
+
+func TestParseDHCPv4Client(t *testing.T) {
+	var want, got *dev.DHCPClientInfo
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	dhcpLayer := &layers.DHCPv4{
+		Operation:    layers.DHCPOpRequest,
+		HardwareType: layers.LinkTypeEthernet,
+		ClientHWAddr: net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		Options: layers.DHCPOptions{
+			layers.NewDHCPOption(layers.DHCPOptMessageType,
+				[]byte{byte(layers.DHCPMsgTypeDiscover)}),
+			layers.NewDHCPOption(layers.DHCPOptClientID,
+				[]byte{1, 1, 2, 3, 4, 5, 6}),
+			layers.NewDHCPOption(layers.DHCPOptHostname,
+				[]byte("host")),
+			layers.NewDHCPOption(layers.DHCPOptClassID,
+				[]byte("MSFT 5.0")),
+			layers.NewDHCPOption(layers.DHCPOptParamsRequest,
+				[]byte{1, 3, 6, 15, 31, 33, 43, 44}),
+			layers.NewDHCPOption(dhcpv4OptFQDN,
+				append([]byte{0x04, 0, 0, 4, 'h', 'o', 's',
+					't', 7}, []byte("example\x03com\x00")...)),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.IPv4zero.To4(),
+		net.IPv4bcast.To4(), 68, 67, dhcpLayer))
+
+	// check results
+	want = &dev.DHCPClientInfo{
+		Name:        "DHCPv4 Client",
+		Hostname:    "host",
+		FQDN:        "host.example.com",
+		VendorClass: "MSFT 5.0",
+		ClientID:    "01:01:02:03:04:05:06",
+		Fingerprint: "1,3,6,15,31,33,43,44",
+	}
+	got = devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).DHCPv4Client
+	if *got != *want {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseDHCPv6Client(t *testing.T) {
+	var want, got *dev.DHCPClientInfo
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	dhcpLayer := &layers.DHCPv6{
+		MsgType:       layers.DHCPv6MsgTypeSolicit,
+		TransactionID: []byte{1, 2, 3},
+		Options: layers.DHCPv6Options{
+			layers.NewDHCPv6Option(layers.DHCPv6OptClientID,
+				[]byte{0, 3, 0, 1, 1, 2, 3, 4, 5, 6}),
+			layers.NewDHCPv6Option(layers.DHCPv6OptOro,
+				[]byte{0, 23, 0, 24}),
+			layers.NewDHCPv6Option(layers.DHCPv6OptVendorClass,
+				append([]byte{0, 0, 1, 55, 0, 8},
+					[]byte("MSFT 5.0")...)),
+			layers.NewDHCPv6Option(layers.DHCPv6OptClientFQDN,
+				append([]byte{0, 4, 'h', 'o', 's', 't', 7},
+					[]byte("example\x03com\x00")...)),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("ff02::1:2"), 546, 547, dhcpLayer))
+
+	// check results
+	want = &dev.DHCPClientInfo{
+		Name:        "DHCPv6 Client",
+		FQDN:        "host.example.com",
+		VendorClass: "311: MSFT 5.0",
+		ClientID:    "00:03:00:01:01:02:03:04:05:06",
+		Fingerprint: "23,24",
+	}
+	got = devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).DHCPv6Client
+	if *got != *want {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseDHCPv4Server(t *testing.T) {
+	var want, got *dev.DHCPServerInfo
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	dhcpLayer := &layers.DHCPv4{
+		Operation:    layers.DHCPOpReply,
+		HardwareType: layers.LinkTypeEthernet,
+		ClientHWAddr: net.HardwareAddr{6, 5, 4, 3, 2, 1},
+		YourClientIP: net.IP{192, 0, 2, 100},
+		Options: layers.DHCPOptions{
+			layers.NewDHCPOption(layers.DHCPOptMessageType,
+				[]byte{byte(layers.DHCPMsgTypeOffer)}),
+			layers.NewDHCPOption(layers.DHCPOptServerID,
+				[]byte{192, 0, 2, 1}),
+			layers.NewDHCPOption(layers.DHCPOptLeaseTime,
+				[]byte{0, 1, 0x51, 0x80}),
+			layers.NewDHCPOption(layers.DHCPOptSubnetMask,
+				[]byte{255, 255, 255, 0}),
+			layers.NewDHCPOption(layers.DHCPOptRouter,
+				[]byte{192, 0, 2, 1}),
+			layers.NewDHCPOption(layers.DHCPOptDNS,
+				[]byte{192, 0, 2, 53, 192, 0, 2, 54}),
+			layers.NewDHCPOption(layers.DHCPOptDomainName,
+				[]byte("example.com")),
+			layers.NewDHCPOption(layers.DHCPOptDomainSearch,
+				[]byte("\x07example\x03com\x00\x03lab\xc0\x00")),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.IP{192, 0, 2, 1},
+		net.IP{192, 0, 2, 100}, 67, 68, dhcpLayer))
+
+	// check results
+	want = &dev.DHCPServerInfo{
+		Name:         "DHCPv4 Server",
+		ServerID:     "192.0.2.1",
+		Address:      "192.0.2.100",
+		SubnetMask:   "255.255.255.0",
+		Routers:      []string{"192.0.2.1"},
+		DNSServers:   []string{"192.0.2.53", "192.0.2.54"},
+		DomainName:   "example.com",
+		DomainSearch: []string{"example.com", "lab.example.com"},
+		LeaseTime:    86400,
+	}
+	got = devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).DHCPv4Server
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseDHCPv6Server(t *testing.T) {
+	var want, got *dev.DHCPServerInfo
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	iana := []byte{
+		// iaid, t1, t2
+		0, 0, 0, 1, 0, 0, 0x0e, 0x10, 0, 0, 0x15, 0x18,
+		// ia address option, length 24
+		0, 5, 0, 24,
+		// address 2001:db8::100
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0x01, 0x00,
+		// preferred and valid lifetime
+		0, 0, 0x1c, 0x20, 0, 1, 0x51, 0x80,
+	}
+	dhcpLayer := &layers.DHCPv6{
+		MsgType:       layers.DHCPv6MsgTypeAdvertise,
+		TransactionID: []byte{1, 2, 3},
+		Options: layers.DHCPv6Options{
+			layers.NewDHCPv6Option(layers.DHCPv6OptServerID,
+				[]byte{0, 3, 0, 1, 1, 2, 3, 4, 5, 6}),
+			layers.NewDHCPv6Option(layers.DHCPv6OptIANA, iana),
+			layers.NewDHCPv6Option(layers.DHCPv6OptDNSServers,
+				net.ParseIP("2001:db8::53")),
+			layers.NewDHCPv6Option(layers.DHCPv6OptDomainList,
+				[]byte("\x07example\x03com\x00")),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+
+	// check results
+	want = &dev.DHCPServerInfo{
+		Name:         "DHCPv6 Server",
+		ServerID:     "00:03:00:01:01:02:03:04:05:06",
+		Address:      "2001:db8::100",
+		DNSServers:   []string{"2001:db8::53"},
+		DomainSearch: []string{"example.com"},
+		LeaseTime:    86400,
+	}
+	got = devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).DHCPv6Server
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseDHCPv6ReplyAddress(t *testing.T) {
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse advertise and reply packets
+	iana := []byte{
+		// iaid, t1, t2
+		0, 0, 0, 1, 0, 0, 0x0e, 0x10, 0, 0, 0x15, 0x18,
+		// ia address option, length 24
+		0, 5, 0, 24,
+		// address 2001:db8::100
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0x01, 0x00,
+		// preferred and valid lifetime
+		0, 0, 0x1c, 0x20, 0, 1, 0x51, 0x80,
+	}
+	dhcpLayer := &layers.DHCPv6{
+		MsgType:       layers.DHCPv6MsgTypeAdvertise,
+		TransactionID: []byte{1, 2, 3},
+		Options: layers.DHCPv6Options{
+			layers.NewDHCPv6Option(layers.DHCPv6OptIANA, iana),
+		},
+	}
+	client := layers.NewMACEndpoint(net.HardwareAddr{6, 5, 4, 3, 2, 1})
+	addr := layers.NewIPEndpoint(net.ParseIP("2001:db8::100"))
+
+	// advertised address should not be assigned to the client
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+	if devices.Get(client) != nil {
+		t.Errorf("got = %v; want = nil", devices.Get(client))
+	}
+
+	// address in reply should be assigned to the client
+	dhcpLayer.MsgType = layers.DHCPv6MsgTypeReply
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+	c := devices.Get(client)
+	if c == nil || c.UCasts.Get(addr) == nil {
+		t.Fatalf("got = nil; want = %s", addr)
+	}
+	if !c.UCasts.Get(addr).DHCPv6 {
+		t.Errorf("got = false; want = true")
+	}
+
+	// reply without ia_na should not assign the previous address
+	devices.Delete(c)
+	dhcpLayer.Options = nil
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+	if devices.Get(client) != nil {
+		t.Errorf("got = %v; want = nil", devices.Get(client))
+	}
+}
+
+func TestParseDHCPRogueServer(t *testing.T) {
+	// set device table and authorized servers
+	devices = &dev.DeviceMap{}
+	if err := SetDHCPServers([]string{"00:00:5e:00:53:01",
+		"192.0.2.1@10"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = SetDHCPServers(nil)
+	}()
+
+	// parse offer from unauthorized server
+	dhcpLayer := &layers.DHCPv4{
+		Operation:    layers.DHCPOpReply,
+		HardwareType: layers.LinkTypeEthernet,
+		ClientHWAddr: net.HardwareAddr{6, 5, 4, 3, 2, 1},
+		Options: layers.DHCPOptions{
+			layers.NewDHCPOption(layers.DHCPOptMessageType,
+				[]byte{byte(layers.DHCPMsgTypeOffer)}),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.IP{192, 0, 2, 1},
+		net.IP{192, 0, 2, 100}, 67, 68, dhcpLayer))
+
+	// parse reply from unauthorized server
+	dhcpv6Layer := &layers.DHCPv6{
+		MsgType:       layers.DHCPv6MsgTypeReply,
+		TransactionID: []byte{1, 2, 3},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpv6Layer))
+
+	// check alert, both messages should update the same alert
+	alerts := devices.Alerts.Get()
+	if len(alerts) != 1 {
+		t.Fatalf("got = %d; want = 1", len(alerts))
+	}
+	want := "rogue-dhcp-server: device 01:02:03:04:05:06, vlan 0: " +
+		"unauthorized dhcp server fe80::1 sent dhcpv6 reply"
+	got := alerts[0].String()
+	if got != want || alerts[0].Count != 2 {
+		t.Errorf("got = %s (%d); want = %s (2)", got, alerts[0].Count,
+			want)
+	}
+
+	// authorize server, no new alerts
+	devices = &dev.DeviceMap{}
+	if err := SetDHCPServers([]string{"01:02:03:04:05:06"}); err != nil {
+		t.Fatal(err)
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.IP{192, 0, 2, 1},
+		net.IP{192, 0, 2, 100}, 67, 68, dhcpLayer))
+	if devices.Alerts.Len() != 0 {
+		t.Errorf("got = %d; want = 0", devices.Alerts.Len())
+	}
+}
+
+func TestParseDHCPv6ClientNotServer(t *testing.T) {
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// client messages should not mark the sender as dhcp server
+	for _, msgType := range []layers.DHCPv6MsgType{
+		layers.DHCPv6MsgTypeSolicit,
+		layers.DHCPv6MsgTypeRequest,
+		layers.DHCPv6MsgTypeRenew,
+		layers.DHCPv6MsgTypeInformationRequest,
+	} {
+		testParseDHCPv6MsgType(msgType)
+	}
+	d := devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6}))
+	if d == nil {
+		t.Fatal("got = nil; want device")
+	}
+	if d.DHCP.Enabled || d.DHCPv6Server != nil {
+		t.Errorf("got = %t, %v; want = false, nil", d.DHCP.Enabled,
+			d.DHCPv6Server)
+	}
+}
+
+func TestParseDHCPv6ServerOnlyMsgs(t *testing.T) {
+	// reconfigure and relay reply messages should mark the sender as
+	// dhcp server
+	for _, msgType := range []layers.DHCPv6MsgType{
+		layers.DHCPv6MsgTypeReconfigure,
+		layers.DHCPv6MsgTypeRelayReply,
+	} {
+		devices = &dev.DeviceMap{}
+		testParseDHCPv6MsgType(msgType)
+		d := devices.Get(layers.NewMACEndpoint(
+			net.HardwareAddr{1, 2, 3, 4, 5, 6}))
+		if d == nil || !d.DHCP.Enabled {
+			t.Errorf("%s: got = false; want = true", msgType)
+		}
+	}
+}
+
+func TestParseDHCPv6ServerReset(t *testing.T) {
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// parse advertise with configuration
+	dhcpLayer := &layers.DHCPv6{
+		MsgType:       layers.DHCPv6MsgTypeAdvertise,
+		TransactionID: []byte{1, 2, 3},
+		Options: layers.DHCPv6Options{
+			layers.NewDHCPv6Option(layers.DHCPv6OptDNSServers,
+				net.ParseIP("2001:db8::53")),
+			layers.NewDHCPv6Option(layers.DHCPv6OptDomainList,
+				[]byte("\x07example\x03com\x00")),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+
+	// parse reply without configuration, old configuration should be
+	// removed
+	dhcpLayer.MsgType = layers.DHCPv6MsgTypeReply
+	dhcpLayer.Options = nil
+	parseDhcp(testParseDHCPCreatePacket(net.ParseIP("fe80::1"),
+		net.ParseIP("fe80::2"), 547, 546, dhcpLayer))
+	want := &dev.DHCPServerInfo{Name: "DHCPv6 Server"}
+	got := devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).DHCPv6Server
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseDHCPCorrelate(t *testing.T) {
+	dev.SetCorrelate(true)
+	defer dev.SetCorrelate(false)
+
+	// add randomized device with client id
+	devices = &dev.DeviceMap{}
+	rand := layers.NewMACEndpoint(net.HardwareAddr{0xd2, 0xa1, 0x19, 0, 0,
+		1})
+	devices.Add(rand).AddDHCPv4Client().ClientID = "01:02:03"
+
+	// parse request with same client id, devices should be correlated
+	dhcpLayer := &layers.DHCPv4{
+		Operation:    layers.DHCPOpRequest,
+		HardwareType: layers.LinkTypeEthernet,
+		ClientHWAddr: net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		Options: layers.DHCPOptions{
+			layers.NewDHCPOption(layers.DHCPOptMessageType,
+				[]byte{byte(layers.DHCPMsgTypeRequest)}),
+			layers.NewDHCPOption(layers.DHCPOptClientID,
+				[]byte{1, 2, 3}),
+		},
+	}
+	parseDhcp(testParseDHCPCreatePacket(net.IPv4zero, net.IPv4bcast, 68,
+		67, dhcpLayer))
+	want := layers.NewMACEndpoint(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	got := devices.Get(rand).Correlated
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got = %v; want = [%s]", got, want)
+	}
+}
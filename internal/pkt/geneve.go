@@ -13,8 +13,7 @@ func parseGeneve(packet gopacket.Packet) {
 		geneve, _ := geneveLayer.(*layers.Geneve)
 		linkSrc, _ := getMacs(packet)
 		dev := devices.Add(linkSrc)
-		g := dev.GENEVEs.Add(geneve.VNI)
-		g.Type = "GENEVE"
+		g := dev.GENEVEs.Add(geneve.VNI, "GENEVE")
 		g.SetTimestamp(packet.Metadata().Timestamp)
 		g.Packets++
 	}
@@ -75,7 +75,7 @@ func TestParseGeneve(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    GENEVE: 42                                   " +
@@ -31,13 +31,15 @@ func parseIgmp(packet gopacket.Packet) {
 		case layers.IGMPMembershipReportV1:
 			debug("IGMPv1 Membership Report")
 			// add IP
-			dev.MCasts.Add(layers.NewIPEndpoint(
-				igmp.GroupAddress))
+			setAddrTimestamp(dev.MCasts.Add(
+				layers.NewIPEndpoint(igmp.GroupAddress)),
+				packet)
 		case layers.IGMPMembershipReportV2:
 			debug("IGMPv2 Membership Report")
 			// add IP
-			dev.MCasts.Add(layers.NewIPEndpoint(
-				igmp.GroupAddress))
+			setAddrTimestamp(dev.MCasts.Add(
+				layers.NewIPEndpoint(igmp.GroupAddress)),
+				packet)
 		case layers.IGMPLeaveGroup:
 			debug("IGMPv1or2 Leave Group")
 			// remove IP
@@ -62,8 +64,10 @@ func parseIgmp(packet gopacket.Packet) {
 				switch v.Type {
 				case layers.IGMPIsEx, layers.IGMPToEx:
 					// add IP
-					dev.MCasts.Add(layers.NewIPEndpoint(
-						v.MulticastAddress))
+					setAddrTimestamp(dev.MCasts.Add(
+						layers.NewIPEndpoint(
+							v.MulticastAddress)),
+						packet)
 				case layers.IGMPIsIn, layers.IGMPToIn:
 					// remove IP
 					dev.MCasts.Del(layers.NewIPEndpoint(
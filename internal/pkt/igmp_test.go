@@ -83,7 +83,7 @@ func TestParseIGMPv1or2(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -109,7 +109,7 @@ func TestParseIGMPv1or2(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -283,7 +283,7 @@ func TestParseIGMPv3(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -317,7 +317,7 @@ func TestParseIGMPv3(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -0,0 +1,146 @@
+package pkt
+
+import (
+	"net"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// lldpAddr converts an address in an lldp tlv with address family family to
+// a string
+func lldpAddr(family layers.IANAAddressFamily, addr []byte) string {
+	switch family {
+	case layers.IANAAddressFamilyIPV4, layers.IANAAddressFamilyIPV6:
+		return net.IP(addr).String()
+	case layers.IANAAddressFamily802:
+		return net.HardwareAddr(addr).String()
+	}
+	return string(addr)
+}
+
+// lldpChassisID converts the lldp chassis id to a string
+func lldpChassisID(id layers.LLDPChassisID) string {
+	switch id.Subtype {
+	case layers.LLDPChassisIDSubTypeMACAddr:
+		return net.HardwareAddr(id.ID).String()
+	case layers.LLDPChassisIDSubTypeNetworkAddr:
+		if len(id.ID) > 0 {
+			family := layers.IANAAddressFamily(id.ID[0])
+			return lldpAddr(family, id.ID[1:])
+		}
+	}
+	return string(id.ID)
+}
+
+// lldpPortID converts the lldp port id to a string
+func lldpPortID(id layers.LLDPPortID) string {
+	switch id.Subtype {
+	case layers.LLDPPortIDSubtypeMACAddr:
+		return net.HardwareAddr(id.ID).String()
+	case layers.LLDPPortIDSubtypeNetworkAddr:
+		if len(id.ID) > 0 {
+			family := layers.IANAAddressFamily(id.ID[0])
+			return lldpAddr(family, id.ID[1:])
+		}
+	}
+	return string(id.ID)
+}
+
+// lldpCapabilities converts the lldp capabilities to a list of strings
+func lldpCapabilities(caps layers.LLDPCapabilities) []string {
+	var c []string
+	for _, capability := range []struct {
+		enabled bool
+		name    string
+	}{
+		{caps.Other, "other"},
+		{caps.Repeater, "repeater"},
+		{caps.Bridge, "bridge"},
+		{caps.WLANAP, "wlan-ap"},
+		{caps.Router, "router"},
+		{caps.Phone, "phone"},
+		{caps.DocSis, "docsis"},
+		{caps.StationOnly, "station"},
+		{caps.CVLAN, "c-vlan"},
+		{caps.SVLAN, "s-vlan"},
+		{caps.TMPR, "tpmr"},
+	} {
+		if capability.enabled {
+			c = append(c, capability.name)
+		}
+	}
+	return c
+}
+
+// lldpMgmtAddrs gets all management addresses from the lldp tlvs; the
+// lldp info layer only contains the last management address
+func lldpMgmtAddrs(values []layers.LinkLayerDiscoveryValue) []string {
+	var addrs []string
+	for _, v := range values {
+		if v.Type != layers.LLDPTLVMgmtAddress || len(v.Value) < 2 {
+			continue
+		}
+		// address string length includes address family
+		addrLen := int(v.Value[0])
+		if addrLen < 1 || len(v.Value) < addrLen+1 {
+			continue
+		}
+		family := layers.IANAAddressFamily(v.Value[1])
+		addr := lldpAddr(family, v.Value[2:addrLen+1])
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// parseLldpInfo parses the lldp info layer and stores it in l
+func parseLldpInfo(packet gopacket.Packet, l *dev.LLDPInfo) {
+	infoLayer := packet.Layer(layers.LayerTypeLinkLayerDiscoveryInfo)
+	if infoLayer == nil {
+		return
+	}
+	info, _ := infoLayer.(*layers.LinkLayerDiscoveryInfo)
+	l.PortDescription = info.PortDescription
+	l.SysName = info.SysName
+	l.SysDescription = info.SysDescription
+	l.SysCapabilities = lldpCapabilities(info.SysCapabilities.EnabledCap)
+
+	// vlan tlvs
+	info8021, err := info.Decode8021()
+	if err != nil {
+		debug("LLDP 802.1 TLV error")
+		countParseError(
+			layers.LayerTypeLinkLayerDiscoveryInfo.String())
+		return
+	}
+	l.PortVLANID = info8021.PVID
+	for _, v := range info8021.VLANNames {
+		l.VLANs = append(l.VLANs, dev.LLDPVLAN{
+			ID:   v.ID,
+			Name: v.Name,
+		})
+	}
+}
+
+// parseLldp parses lldp packets
+func parseLldp(packet gopacket.Packet) {
+	lldpLayer := packet.Layer(layers.LayerTypeLinkLayerDiscovery)
+	if lldpLayer == nil {
+		return
+	}
+	debug("LLDP packet")
+	lldp, _ := lldpLayer.(*layers.LinkLayerDiscovery)
+	linkSrc, _ := getMacs(packet)
+
+	// add device and replace its lldp info with the advertised one
+	device := devices.Add(linkSrc)
+	l := device.AddLLDP()
+	*l = dev.LLDPInfo{TimeInfo: l.TimeInfo}
+	l.SetTimestamp(packet.Metadata().Timestamp)
+	l.ChassisID = lldpChassisID(lldp.ChassisID)
+	l.PortID = lldpPortID(lldp.PortID)
+	l.MgmtAddresses = lldpMgmtAddrs(lldp.Values)
+	parseLldpInfo(packet, l)
+}
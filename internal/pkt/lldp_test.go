@@ -0,0 +1,124 @@
+package pkt
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// testLLDPValue creates an lldp tlv with type t and value v
+func testLLDPValue(t layers.LLDPTLVType,
+	v []byte) layers.LinkLayerDiscoveryValue {
+	return layers.LinkLayerDiscoveryValue{
+		Type:   t,
+		Length: uint16(len(v)),
+		Value:  v,
+	}
+}
+
+func testParseLLDPCreatePacket() gopacket.Packet {
+	// prepare creation of packet
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	pktBuf := gopacket.NewSerializeBuffer()
+
+	// create headers
+	ethLayer := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{0x1, 0x80, 0xC2, 0x0, 0x0, 0xe},
+		EthernetType: layers.EthernetTypeLinkLayerDiscovery,
+	}
+	lldpLayer := &layers.LinkLayerDiscovery{
+		ChassisID: layers.LLDPChassisID{
+			Subtype: layers.LLDPChassisIDSubTypeMACAddr,
+			ID:      []byte{1, 2, 3, 4, 5, 6},
+		},
+		PortID: layers.LLDPPortID{
+			Subtype: layers.LLDPPortIDSubtypeIfaceName,
+			ID:      []byte("eth1"),
+		},
+		TTL: 120,
+		Values: []layers.LinkLayerDiscoveryValue{
+			testLLDPValue(layers.LLDPTLVPortDescription,
+				[]byte("uplink")),
+			testLLDPValue(layers.LLDPTLVSysName,
+				[]byte("switch1")),
+			testLLDPValue(layers.LLDPTLVSysDescription,
+				[]byte("test switch")),
+			// capabilities: bridge, router
+			testLLDPValue(layers.LLDPTLVSysCapabilities,
+				[]byte{0x00, 0x14, 0x00, 0x14}),
+			// management address: 192.0.2.1, ifindex 1, no oid
+			testLLDPValue(layers.LLDPTLVMgmtAddress,
+				[]byte{5, 1, 192, 0, 2, 1, 2, 0, 0, 0, 1, 0}),
+			// management address: 00:00:5e:00:53:01
+			testLLDPValue(layers.LLDPTLVMgmtAddress,
+				[]byte{7, 6, 0x00, 0x00, 0x5e, 0x00, 0x53,
+					0x01, 2, 0, 0, 0, 1, 0}),
+			// 802.1 port vlan id: 1
+			testLLDPValue(layers.LLDPTLVOrgSpecific,
+				[]byte{0x00, 0x80, 0xc2, 1, 0, 1}),
+			// 802.1 vlan name: 10, voice
+			testLLDPValue(layers.LLDPTLVOrgSpecific,
+				[]byte{0x00, 0x80, 0xc2, 3, 0, 10, 5,
+					'v', 'o', 'i', 'c', 'e'}),
+		},
+	}
+
+	// serialize to buffer
+	err := gopacket.SerializeLayers(pktBuf, opts, ethLayer, lldpLayer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// create packet from buffer
+	pkt := gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+	return pkt
+}
+
+func TestParseLLDP(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	parseLldp(testParseLLDPCreatePacket())
+
+	// check output
+	devices.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    LLDP: true                                   " +
+		"(age: -1)\n" +
+		"      Chassis ID: 01:02:03:04:05:06\n" +
+		"      Port ID: eth1\n" +
+		"      Port Description: uplink\n" +
+		"      System Name: switch1\n" +
+		"      System Description: test switch\n" +
+		"      System Capabilities: bridge, router\n" +
+		"      Management Address: 192.0.2.1\n" +
+		"      Management Address: 00:00:5e:00:53:01\n" +
+		"      Port VLAN ID: 1\n" +
+		"      VLAN: 10 (voice)\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package pkt
+
+import (
+	"strings"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+)
+
+// mdns constants
+const (
+	mdnsPort     = 5353
+	mdnsServices = "_services._dns-sd._udp.local"
+)
+
+// parseMdns parses mdns (multicast dns) responses
+func parseMdns(packet gopacket.Packet) {
+	udpLayer := packet.Layer(layers.LayerTypeUDP)
+	if udpLayer == nil {
+		return
+	}
+	udp, _ := udpLayer.(*layers.UDP)
+	if udp.SrcPort != mdnsPort {
+		return
+	}
+
+	// mdns is not decoded by gopacket, decode it as dns
+	dns := &layers.DNS{}
+	err := dns.DecodeFromBytes(udp.Payload, gopacket.NilDecodeFeedback)
+	if err != nil {
+		debug("mDNS decoding error")
+		countParseError("mDNS")
+		return
+	}
+	if !dns.QR {
+		// only responses contain announcements
+		return
+	}
+	debug("mDNS Response")
+	linkSrc, _ := getMacs(packet)
+	timestamp := packet.Metadata().Timestamp
+
+	// add device and mdns info
+	dev := devices.Add(linkSrc)
+	m := dev.AddMDNS()
+	m.SetTimestamp(timestamp)
+
+	// parse records in answers and additional records
+	var records []layers.DNSResourceRecord
+	records = append(records, dns.Answers...)
+	records = append(records, dns.Additionals...)
+	for _, r := range records {
+		name := string(r.Name)
+		switch r.Type {
+		case layers.DNSTypeA, layers.DNSTypeAAAA:
+			// hostname
+			m.AddHostname(name)
+		case layers.DNSTypePTR:
+			if strings.HasSuffix(name, ".arpa") {
+				// reverse lookup of hostname
+				m.AddHostname(string(r.PTR))
+				continue
+			}
+			if name == mdnsServices {
+				// service type enumeration
+				continue
+			}
+			if r.TTL == 0 {
+				// goodbye, service is going away
+				m.DelService(string(r.PTR))
+				continue
+			}
+			s := m.AddService(string(r.PTR))
+			s.SetTimestamp(timestamp)
+		case layers.DNSTypeSRV:
+			if r.TTL == 0 {
+				m.DelService(name)
+				continue
+			}
+			s := m.AddService(name)
+			s.SetTimestamp(timestamp)
+			s.Host = string(r.SRV.Name)
+			s.Port = r.SRV.Port
+		case layers.DNSTypeTXT:
+			if r.TTL == 0 {
+				m.DelService(name)
+				continue
+			}
+			s := m.AddService(name)
+			s.SetTimestamp(timestamp)
+			s.TXT = nil
+			for _, txt := range r.TXTs {
+				if len(txt) > 0 {
+					s.TXT = append(s.TXT, string(txt))
+				}
+			}
+		}
+	}
+	devices.UpdateCorrelation(dev)
+}
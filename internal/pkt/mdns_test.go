@@ -0,0 +1,157 @@
+package pkt
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+func testParseMDNSCreatePacket(ttl uint32) gopacket.Packet {
+	// prepare creation of packet
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+	pktBuf := gopacket.NewSerializeBuffer()
+
+	// create headers
+	ethLayer := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{0x1, 0x0, 0x5e, 0x0, 0x0, 0xfb},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ipLayer := &layers.IPv4{
+		Version:  4,
+		TTL:      255,
+		SrcIP:    net.IP{192, 0, 2, 1},
+		DstIP:    net.IP{224, 0, 0, 251},
+		Protocol: layers.IPProtocolUDP,
+	}
+	udpLayer := &layers.UDP{
+		SrcPort: 5353,
+		DstPort: 5353,
+	}
+	udpLayer.SetNetworkLayerForChecksum(ipLayer)
+	service := []byte("My Printer._ipp._tcp.local")
+	dnsLayer := &layers.DNS{
+		QR:     true,
+		AA:     true,
+		OpCode: layers.DNSOpCodeQuery,
+		Answers: []layers.DNSResourceRecord{
+			{
+				Name:  []byte("_services._dns-sd._udp.local"),
+				Type:  layers.DNSTypePTR,
+				Class: layers.DNSClassIN,
+				TTL:   ttl,
+				PTR:   []byte("_ipp._tcp.local"),
+			},
+			{
+				Name:  []byte("_ipp._tcp.local"),
+				Type:  layers.DNSTypePTR,
+				Class: layers.DNSClassIN,
+				TTL:   ttl,
+				PTR:   service,
+			},
+		},
+		Additionals: []layers.DNSResourceRecord{
+			{
+				Name:  service,
+				Type:  layers.DNSTypeSRV,
+				Class: layers.DNSClassIN,
+				TTL:   ttl,
+				SRV: layers.DNSSRV{
+					Port: 631,
+					Name: []byte("printer.local"),
+				},
+			},
+			{
+				Name:  service,
+				Type:  layers.DNSTypeTXT,
+				Class: layers.DNSClassIN,
+				TTL:   ttl,
+				TXTs: [][]byte{
+					[]byte("txtvers=1"),
+					[]byte("ty=Printer"),
+				},
+			},
+			{
+				Name:  []byte("printer.local"),
+				Type:  layers.DNSTypeA,
+				Class: layers.DNSClassIN,
+				TTL:   ttl,
+				IP:    net.IP{192, 0, 2, 1},
+			},
+		},
+	}
+
+	// serialize to buffer
+	err := gopacket.SerializeLayers(pktBuf, opts,
+		ethLayer, ipLayer, udpLayer, dnsLayer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// create packet from buffer
+	pkt := gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+	return pkt
+}
+
+func TestParseMDNS(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create and parse packet
+	parseMdns(testParseMDNSCreatePacket(120))
+
+	// check output
+	devices.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    mDNS: true                                   " +
+		"(age: -1)\n" +
+		"      Hostname: printer.local\n" +
+		"      Service: My Printer._ipp._tcp.local " +
+		"(printer.local:631)\n" +
+		"        TXT: txtvers=1, ty=Printer\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+
+	// parse goodbye packet, service should be removed
+	buf.Reset()
+	parseMdns(testParseMDNSCreatePacket(0))
+	devices.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 0)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
+		"(age: -1, pkts: 0)\n" +
+		"  Properties:\n" +
+		"    mDNS: true                                   " +
+		"(age: -1)\n" +
+		"      Hostname: printer.local\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+}
@@ -49,7 +49,8 @@ func parseMld(packet gopacket.Packet) {
 		netSrc, _ := getIps(packet)
 		dev := devices.Add(linkSrc)
 		dev.UCasts.Add(netSrc)
-		dev.MCasts.Add(layers.NewIPEndpoint(report.MulticastAddress))
+		setAddrTimestamp(dev.MCasts.Add(
+			layers.NewIPEndpoint(report.MulticastAddress)), packet)
 		return
 	}
 
@@ -81,8 +82,9 @@ func parseMld(packet gopacket.Packet) {
 			switch v.RecordType {
 			case mldv2IsEx, mldv2ToEx:
 				// add IP
-				dev.MCasts.Add(layers.NewIPEndpoint(
-					v.MulticastAddress))
+				setAddrTimestamp(dev.MCasts.Add(
+					layers.NewIPEndpoint(v.MulticastAddress)),
+					packet)
 			case mldv2IsIn, mldv2ToIn:
 				// remove IP
 				dev.MCasts.Del(layers.NewIPEndpoint(
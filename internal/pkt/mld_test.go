@@ -96,7 +96,7 @@ func TestParseMLDv1(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -125,7 +125,7 @@ func TestParseMLDv1(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -257,7 +257,7 @@ func TestParseMLDv2(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
@@ -292,7 +292,7 @@ func TestParseMLDv2(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
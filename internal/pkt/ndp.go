@@ -1,22 +1,234 @@
 package pkt
 
 import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
 	"github.com/gopacket/gopacket"
 	"github.com/gopacket/gopacket/layers"
+
+	"github.com/hwipl/listnd/internal/dev"
+)
+
+// ndp constants not defined in gopacket
+const (
+	ndpOptRouteInfo layers.ICMPv6Opt = 24
+	ndpOptRDNSS     layers.ICMPv6Opt = 25
+	ndpOptDNSSL     layers.ICMPv6Opt = 31
+
+	ndpRAFlagManaged = 0x80
+	ndpRAFlagOther   = 0x40
+
+	// neighbor advertisements for a tentative address within this window
+	// after a dad probe are considered a dad failure
+	ndpDADWindow = 10 * time.Second
+)
+
+var (
+	// ndpUnspecified is the source address of dad probes
+	ndpUnspecified = layers.NewIPEndpoint(net.IPv6unspecified)
 )
 
+// parseDadProbe records the tentative address target of the sender of the
+// dad probe in packet
+func parseDadProbe(packet gopacket.Packet, target gopacket.Endpoint) {
+	linkSrc, _ := getMacs(packet)
+	timestamp := packet.Metadata().Timestamp
+
+	// add tentative address to device and probe to dad probes
+	device := devices.Add(linkSrc)
+	setAddrTimestamp(device.Tentative.Add(target), packet)
+	if p := devices.DADProbes.Add(getVlan(packet), target); p != nil {
+		p.SetTimestamp(timestamp)
+		p.Claim(linkSrc).SetTimestamp(timestamp)
+	}
+}
+
+// checkDadFailure raises alerts for all devices that recently probed the
+// tentative address target, if another device advertises it in packet
+func checkDadFailure(packet gopacket.Packet, target gopacket.Endpoint) {
+	vlan := getVlan(packet)
+	p := devices.DADProbes.Get(vlan, target)
+	if p == nil {
+		return
+	}
+	linkSrc, _ := getMacs(packet)
+	timestamp := packet.Metadata().Timestamp
+	for mac, claim := range p.Claims {
+		if mac == linkSrc ||
+			timestamp.Sub(claim.Timestamp) > ndpDADWindow {
+			continue
+		}
+		debug("DAD Failure")
+		devices.Alerts.Add(dev.AlertDADFailure, mac, vlan,
+			target.String(), fmt.Sprintf("tentative address %s of %s already in use "+
+				"by %s", target, mac, linkSrc), timestamp)
+	}
+}
+
+// ndpPreference returns the router preference in the preference bits of
+// the flags f as string
+func ndpPreference(f uint8) string {
+	switch (f >> 3) & 0x03 {
+	case 0:
+		return "medium"
+	case 1:
+		return "high"
+	case 3:
+		return "low"
+	}
+	return "reserved"
+}
+
+// ndpRouteInfo parses the route information option o
+func ndpRouteInfo(o layers.ICMPv6Option) (dev.RARoute, bool) {
+	if len(o.Data) < 6 || int(o.Data[0]) > 8*net.IPv6len {
+		return dev.RARoute{}, false
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, o.Data[6:])
+	mask := net.CIDRMask(int(o.Data[0]), 8*net.IPv6len)
+	prefix := net.IPNet{
+		IP:   ip.Mask(mask),
+		Mask: mask,
+	}
+	return dev.RARoute{
+		Prefix:     prefix.String(),
+		Preference: ndpPreference(o.Data[1]),
+		Lifetime:   binary.BigEndian.Uint32(o.Data[2:6]),
+	}, true
+}
+
+// parseRAInfo replaces r with the details in router advertisement adv seen
+// at timestamp
+func parseRAInfo(adv *layers.ICMPv6RouterAdvertisement, r *dev.RAInfo,
+	timestamp time.Time) {
+	*r = dev.RAInfo{TimeInfo: r.TimeInfo}
+	r.SetTimestamp(timestamp)
+	r.Managed = adv.Flags&ndpRAFlagManaged != 0
+	r.OtherConfig = adv.Flags&ndpRAFlagOther != 0
+	r.Preference = ndpPreference(adv.Flags)
+	r.RouterLifetime = adv.RouterLifetime
+	r.ReachableTime = adv.ReachableTime
+	r.RetransTimer = adv.RetransTimer
+	r.HopLimit = adv.HopLimit
+
+	for _, o := range adv.Options {
+		switch o.Type {
+		case layers.ICMPv6OptMTU:
+			if len(o.Data) >= 6 {
+				r.MTU = binary.BigEndian.Uint32(o.Data[2:6])
+			}
+		case ndpOptRDNSS:
+			if len(o.Data) < 6 {
+				continue
+			}
+			r.RDNSSLifetime = binary.BigEndian.Uint32(o.Data[2:6])
+			r.RDNSS = append(r.RDNSS,
+				dhcpIPs(o.Data[6:], net.IPv6len)...)
+		case ndpOptDNSSL:
+			if len(o.Data) < 6 {
+				continue
+			}
+			r.DNSSLLifetime = binary.BigEndian.Uint32(o.Data[2:6])
+			for _, name := range getDomainNames(o.Data[6:]) {
+				// skip padding
+				if name != "" {
+					r.DNSSL = append(r.DNSSL, name)
+				}
+			}
+		case ndpOptRouteInfo:
+			if route, ok := ndpRouteInfo(o); ok {
+				r.Routes = append(r.Routes, route)
+			}
+		}
+	}
+}
+
+// ndpPrefix returns the prefix in the prefix information option o as string
+func ndpPrefix(o layers.ICMPv6Option) string {
+	if len(o.Data) < 30 {
+		return ""
+	}
+	mask := net.CIDRMask(int(o.Data[0]), 8*net.IPv6len)
+	prefix := net.IPNet{
+		IP:   net.IP(o.Data[14:30]).Mask(mask),
+		Mask: mask,
+	}
+	return prefix.String()
+}
+
+// checkRouter raises alerts if the sender of the router advertisement adv
+// in packet is not an authorized router, if it advertises unexpected
+// prefixes or if its router lifetime is zero
+func checkRouter(packet gopacket.Packet,
+	adv *layers.ICMPv6RouterAdvertisement) {
+	linkSrc, _ := getMacs(packet)
+	netSrc, _ := getIps(packet)
+	vlan := getVlan(packet)
+	timestamp := packet.Metadata().Timestamp
+
+	// check router lifetime
+	if adv.RouterLifetime == 0 {
+		debug("Router Lifetime Zero")
+		devices.Alerts.Add(dev.AlertRouterLifetimeZero, linkSrc, vlan,
+			"", fmt.Sprintf("router %s sent router advertisement "+
+				"with router lifetime 0", netSrc), timestamp)
+	}
+
+	// check router
+	if routers != nil &&
+		!routers.allows(vlan, linkSrc.String(), netSrc.String()) {
+		debug("Rogue Router")
+		devices.Alerts.Add(dev.AlertRogueRouter, linkSrc, vlan, "",
+			fmt.Sprintf("unauthorized router %s sent router "+
+				"advertisement", netSrc), timestamp)
+		return
+	}
+
+	// check prefixes of known router
+	if routerPrefixes == nil {
+		return
+	}
+	for _, o := range adv.Options {
+		if o.Type != layers.ICMPv6OptPrefixInfo {
+			continue
+		}
+		prefix := ndpPrefix(o)
+		if routerPrefixes.allows(vlan, prefix) {
+			continue
+		}
+		debug("Unexpected Prefix")
+		devices.Alerts.Add(dev.AlertUnexpectedPrefix, linkSrc, vlan,
+			prefix, fmt.Sprintf("router %s advertised unexpected prefix %s",
+				netSrc, prefix), timestamp)
+	}
+}
+
 // parseNdp parses neighbor discovery protocol packets
 func parseNdp(packet gopacket.Packet) {
 	nsolLayer := packet.Layer(layers.LayerTypeICMPv6NeighborSolicitation)
 	if nsolLayer != nil {
 		debug("Neighbor Solicitation")
 		// neighbor solicitation, get src mac and src ip
+		sol, _ := nsolLayer.(*layers.ICMPv6NeighborSolicitation)
 		linkSrc, _ := getMacs(packet)
 		netSrc, _ := getIps(packet)
 
+		// check for dad probe
+		if netSrc == ndpUnspecified {
+			debug("DAD Probe")
+			parseDadProbe(packet,
+				layers.NewIPEndpoint(sol.TargetAddress))
+			return
+		}
+
 		// add to table
 		dev := devices.Add(linkSrc)
 		dev.UCasts.Add(netSrc)
+		dev.Tentative.Del(netSrc)
 
 		return
 	}
@@ -31,7 +243,11 @@ func parseNdp(packet gopacket.Packet) {
 
 		// add to table
 		dev := devices.Add(linkSrc)
-		dev.UCasts.Add(targetIP)
+		setAddrTimestamp(dev.UCasts.Add(targetIP), packet)
+		dev.Tentative.Del(targetIP)
+
+		// check if another device probed the address
+		checkDadFailure(packet, targetIP)
 
 		return
 	}
@@ -66,11 +282,18 @@ func parseNdp(packet gopacket.Packet) {
 		dev.Router.Enable()
 		dev.Router.SetTimestamp(timestamp)
 
-		// flush prefixes and refill with advertised ones
+		// check for rogue routers
 		adv, _ := radvLayer.(*layers.ICMPv6RouterAdvertisement)
+		checkRouter(packet, adv)
+
+		// replace router advertisement details
+		parseRAInfo(adv, dev.AddRA(), timestamp)
+
+		// flush prefixes and refill with advertised ones
 		dev.Prefixes.Clear()
 		for i := range adv.Options {
-			if adv.Options[i].Type == layers.ICMPv6OptPrefixInfo {
+			if adv.Options[i].Type == layers.ICMPv6OptPrefixInfo &&
+				len(adv.Options[i].Data) >= 30 {
 				p := dev.Prefixes.Add(adv.Options[i])
 				p.SetTimestamp(timestamp)
 			}
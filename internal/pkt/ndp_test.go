@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"log"
 	"net"
+	"reflect"
 	"testing"
 
 	"github.com/gopacket/gopacket"
@@ -14,6 +15,16 @@ import (
 func testParseNDPCreatePacket(
 	typeCode layers.ICMPv6TypeCode,
 	ndpLayer gopacket.SerializableLayer,
+) gopacket.Packet {
+	return testParseNDPCreatePacketFrom(net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		net.ParseIP("::1"), typeCode, ndpLayer)
+}
+
+func testParseNDPCreatePacketFrom(
+	srcMAC net.HardwareAddr,
+	srcIP net.IP,
+	typeCode layers.ICMPv6TypeCode,
+	ndpLayer gopacket.SerializableLayer,
 ) gopacket.Packet {
 	// prepare creation of packet
 	opts := gopacket.SerializeOptions{
@@ -24,12 +35,12 @@ func testParseNDPCreatePacket(
 
 	// create headers
 	ethLayer := &layers.Ethernet{
-		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		SrcMAC:       srcMAC,
 		DstMAC:       net.HardwareAddr{6, 5, 4, 3, 2, 1},
 		EthernetType: layers.EthernetTypeIPv6,
 	}
 	ipLayer := &layers.IPv6{
-		SrcIP:      net.ParseIP("::1"),
+		SrcIP:      srcIP,
 		DstIP:      net.ParseIP("::2"),
 		NextHeader: layers.IPProtocolICMPv6,
 	}
@@ -76,7 +87,7 @@ func TestParseNDPNeighborSolicitation(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: ::1                                      " +
@@ -112,7 +123,7 @@ func TestParseNDPNeighborAdvertisement(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: fe80::1                                  " +
@@ -146,7 +157,7 @@ func TestParseNDPRouterSolicitation(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: ::1                                      " +
@@ -191,18 +202,258 @@ func TestParseNDPRouterAdvertisement(t *testing.T) {
 		"(pkts: 0)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 0)\n" +
 		"  Properties:\n" +
 		"    Router: true                                 " +
 		"(age: -1)\n" +
+		"      Managed: false\n" +
+		"      Other Config: false\n" +
+		"      Preference: medium\n" +
+		"      Router Lifetime: 0\n" +
+		"      Reachable Time: 0\n" +
+		"      Retrans Timer: 0\n" +
+		"      Hop Limit: 0\n" +
 		"      Prefix: 2001::/16                          " +
 		"(age: -1)\n" +
+		"        Valid Lifetime: 0\n" +
+		"        Preferred Lifetime: 0\n" +
+		"        On-Link: false\n" +
+		"        Autonomous: false\n" +
 		"  Unicast Addresses:\n" +
 		"    IP: ::1                                      " +
-		"(age: -1, pkts: 0)\n\n"
+		"(age: -1, pkts: 0)\n\n" +
+		"=================================================" +
+		"=====================\n" +
+		"Alerts: 1\n" +
+		"=================================================" +
+		"=====================\n" +
+		"Alert: router-lifetime-zero                     " +
+		" (age: -1, count: 1)\n" +
+		"  Device: 01:02:03:04:05:06\n" +
+		"  Message: router ::1 sent router advertisement " +
+		"with router lifetime 0\n\n"
 	got = buf.String()
 	if got != want {
 		t.Errorf("got = %s; want = %s", got, want)
 	}
 }
+
+func testParseNDPCreateRA(prefix string, lifetime uint16) gopacket.Packet {
+	_, p, _ := net.ParseCIDR(prefix)
+	ones, _ := p.Mask.Size()
+	prefixInfo := [30]byte{}
+	prefixInfo[0] = byte(ones)
+	copy(prefixInfo[14:], p.IP)
+	ndpLayer := &layers.ICMPv6RouterAdvertisement{
+		RouterLifetime: lifetime,
+		Options: layers.ICMPv6Options{
+			{
+				Type: layers.ICMPv6OptPrefixInfo,
+				Data: prefixInfo[:],
+			},
+		},
+	}
+	return testParseNDPCreatePacket(layers.ICMPv6TypeRouterAdvertisement,
+		ndpLayer)
+}
+
+func TestNdpPrefix(t *testing.T) {
+	prefixInfo := [30]byte{}
+	prefixInfo[0] = 64
+	copy(prefixInfo[14:], net.ParseIP("2001:db8::1"))
+	want := "2001:db8::/64"
+	got := ndpPrefix(layers.ICMPv6Option{
+		Type: layers.ICMPv6OptPrefixInfo,
+		Data: prefixInfo[:],
+	})
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+
+	// test invalid option
+	got = ndpPrefix(layers.ICMPv6Option{
+		Type: layers.ICMPv6OptPrefixInfo,
+		Data: prefixInfo[:10],
+	})
+	if got != "" {
+		t.Errorf("got = %s; want = ", got)
+	}
+}
+
+func TestParseNDPRogueRouter(t *testing.T) {
+	var want, got []string
+	defer func() {
+		_ = SetRouters(nil)
+		_ = SetRouterPrefixes(nil)
+	}()
+
+	// getAlerts returns the types and messages of all alerts
+	getAlerts := func() []string {
+		var alerts []string
+		for _, a := range devices.Alerts.Get() {
+			alerts = append(alerts, a.Type+": "+a.Message)
+		}
+		return alerts
+	}
+
+	// test unknown router
+	devices = &dev.DeviceMap{}
+	if err := SetRouters([]string{"fe80::1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRouterPrefixes([]string{"2001:db8::/64"}); err != nil {
+		t.Fatal(err)
+	}
+	parseNdp(testParseNDPCreateRA("2001:db8:1::/64", 1800))
+	want = []string{
+		"rogue-router: unauthorized router ::1 sent router " +
+			"advertisement",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+
+	// test known router with expected and unexpected prefix
+	devices = &dev.DeviceMap{}
+	if err := SetRouters([]string{"01:02:03:04:05:06"}); err != nil {
+		t.Fatal(err)
+	}
+	parseNdp(testParseNDPCreateRA("2001:db8::/64", 1800))
+	if devices.Alerts.Len() != 0 {
+		t.Errorf("got = %v; want = []", getAlerts())
+	}
+	parseNdp(testParseNDPCreateRA("2001:db8:1::/64", 1800))
+	want = []string{
+		"unexpected-prefix: router ::1 advertised unexpected " +
+			"prefix 2001:db8:1::/64",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+
+	// test router lifetime zero
+	devices = &dev.DeviceMap{}
+	parseNdp(testParseNDPCreateRA("2001:db8::/64", 0))
+	want = []string{
+		"router-lifetime-zero: router ::1 sent router " +
+			"advertisement with router lifetime 0",
+	}
+	got = getAlerts()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseNDPRouterAdvertisementDetails(t *testing.T) {
+	// set device table
+	devices = &dev.DeviceMap{}
+
+	// create packet, options are serialized in reverse order
+	rdnss := append([]byte{0, 0, 0, 0, 0x02, 0x58},
+		net.ParseIP("2001:db8::53")...)
+	dnssl := []byte("\x00\x00\x00\x00\x02\x58\x07example\x03com\x00" +
+		"\x00\x00\x00")
+	route := append([]byte{48, 0x08, 0, 0, 0x07, 0x08},
+		net.ParseIP("2001:db8:1::")[:8]...)
+	mtu := []byte{0, 0, 0, 0, 0x05, 0xdc}
+	ndpLayer := &layers.ICMPv6RouterAdvertisement{
+		HopLimit:       64,
+		Flags:          0xc0 | 0x18,
+		RouterLifetime: 1800,
+		ReachableTime:  30000,
+		RetransTimer:   1000,
+		Options: layers.ICMPv6Options{
+			{Type: ndpOptRouteInfo, Data: route},
+			{Type: ndpOptDNSSL, Data: dnssl},
+			{Type: ndpOptRDNSS, Data: rdnss},
+			{Type: layers.ICMPv6OptMTU, Data: mtu},
+		},
+	}
+	parseNdp(testParseNDPCreatePacket(
+		layers.ICMPv6TypeRouterAdvertisement, ndpLayer))
+
+	// check results
+	want := &dev.RAInfo{
+		Managed:        true,
+		OtherConfig:    true,
+		Preference:     "low",
+		RouterLifetime: 1800,
+		ReachableTime:  30000,
+		RetransTimer:   1000,
+		HopLimit:       64,
+		MTU:            1500,
+		RDNSS:          []string{"2001:db8::53"},
+		RDNSSLifetime:  600,
+		DNSSL:          []string{"example.com"},
+		DNSSLLifetime:  600,
+		Routes: []dev.RARoute{
+			{
+				Prefix:     "2001:db8:1::/48",
+				Preference: "high",
+				Lifetime:   1800,
+			},
+		},
+	}
+	got := devices.Get(layers.NewMACEndpoint(
+		net.HardwareAddr{1, 2, 3, 4, 5, 6})).RA
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v; want = %v", got, want)
+	}
+}
+
+func TestParseNDPDuplicateAddressDetection(t *testing.T) {
+	// set device table
+	devices = &dev.DeviceMap{}
+	mac1 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}
+	mac2 := net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}
+	target := net.ParseIP("fe80::1")
+	targetIP := layers.NewIPEndpoint(target)
+
+	// test dad probe
+	parseNdp(testParseNDPCreatePacketFrom(mac1, net.IPv6unspecified,
+		layers.ICMPv6TypeNeighborSolicitation,
+		&layers.ICMPv6NeighborSolicitation{TargetAddress: target}))
+	device := devices.Get(layers.NewMACEndpoint(mac1))
+	if device.Tentative.Get(targetIP) == nil {
+		t.Errorf("got = nil; want tentative address %s", target)
+	}
+	if device.UCasts.Get(targetIP) != nil {
+		t.Errorf("got = %s; want nil", device.UCasts.Get(targetIP))
+	}
+
+	// test neighbor advertisement from other device
+	parseNdp(testParseNDPCreatePacketFrom(mac2, target,
+		layers.ICMPv6TypeNeighborAdvertisement,
+		&layers.ICMPv6NeighborAdvertisement{TargetAddress: target}))
+	alerts := devices.Alerts.Get()
+	if len(alerts) != 1 {
+		t.Fatalf("got = %d; want = 1", len(alerts))
+	}
+	want := "dad-failure: device 00:00:5e:00:53:01, vlan 0: tentative " +
+		"address fe80::1 of 00:00:5e:00:53:01 already in use by " +
+		"00:00:5e:00:53:02"
+	got := alerts[0].String()
+	if got != want {
+		t.Errorf("got = %s; want = %s", got, want)
+	}
+
+	// test neighbor advertisement from probing device, no dad failure
+	devices = &dev.DeviceMap{}
+	parseNdp(testParseNDPCreatePacketFrom(mac1, net.IPv6unspecified,
+		layers.ICMPv6TypeNeighborSolicitation,
+		&layers.ICMPv6NeighborSolicitation{TargetAddress: target}))
+	parseNdp(testParseNDPCreatePacketFrom(mac1, target,
+		layers.ICMPv6TypeNeighborAdvertisement,
+		&layers.ICMPv6NeighborAdvertisement{TargetAddress: target}))
+	device = devices.Get(layers.NewMACEndpoint(mac1))
+	if device.Tentative.Get(targetIP) != nil ||
+		device.UCasts.Get(targetIP) == nil {
+		t.Errorf("tentative address not moved to unicast addresses")
+	}
+	if devices.Alerts.Len() != 0 {
+		t.Errorf("got = %d; want = 0", devices.Alerts.Len())
+	}
+}
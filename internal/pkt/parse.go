@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/gopacket/gopacket"
+	"github.com/gopacket/gopacket/layers"
 
 	"github.com/hwipl/listnd/internal/dev"
 )
@@ -15,6 +17,15 @@ var (
 	debugOut  io.Writer = os.Stdout
 	withPeers bool
 	devices   *dev.DeviceMap
+
+	// parseErrors counts packets that could not be decoded by layer;
+	// protected by the device table lock
+	parseErrors = make(map[string]int)
+
+	// allowlists for rogue device detection
+	dhcpServers    *allowList
+	routers        *allowList
+	routerPrefixes *allowList
 )
 
 // SetDebug enables or disables debug output
@@ -32,6 +43,54 @@ func SetDevices(devs *dev.DeviceMap) {
 	devices = devs
 }
 
+// SetDHCPServers sets the authorized dhcp servers; each server is a MAC or
+// IP address with an optional VLAN, e.g., "00:00:5e:00:53:01@10"; if no
+// servers are set, rogue dhcp server detection is disabled
+func SetDHCPServers(servers []string) error {
+	if len(servers) == 0 {
+		dhcpServers = nil
+		return nil
+	}
+	a, err := newAllowList(servers)
+	if err != nil {
+		return err
+	}
+	dhcpServers = a
+	return nil
+}
+
+// SetRouters sets the authorized ipv6 routers; each router is a MAC or IP
+// address with an optional VLAN; if no routers are set, rogue router
+// detection is disabled
+func SetRouters(list []string) error {
+	if len(list) == 0 {
+		routers = nil
+		return nil
+	}
+	a, err := newAllowList(list)
+	if err != nil {
+		return err
+	}
+	routers = a
+	return nil
+}
+
+// SetRouterPrefixes sets the authorized prefixes in ipv6 router
+// advertisements; each prefix is an IP prefix with an optional VLAN, e.g.,
+// "2001:db8::/64@10"; if no prefixes are set, prefix checking is disabled
+func SetRouterPrefixes(prefixes []string) error {
+	if len(prefixes) == 0 {
+		routerPrefixes = nil
+		return nil
+	}
+	a, err := newAllowList(prefixes)
+	if err != nil {
+		return err
+	}
+	routerPrefixes = a
+	return nil
+}
+
 // debug prints text if in debug mode
 func debug(text string) {
 	if debugMode {
@@ -63,6 +122,102 @@ func getIps(packet gopacket.Packet) (gopacket.Endpoint, gopacket.Endpoint) {
 	return netSrc, netDst
 }
 
+// getVlan is a helper for getting the vlan id of packet, 0 if untagged
+func getVlan(packet gopacket.Packet) uint32 {
+	if vlanLayer := packet.Layer(layers.LayerTypeDot1Q); vlanLayer != nil {
+		vlan, _ := vlanLayer.(*layers.Dot1Q)
+		return uint32(vlan.VLANIdentifier)
+	}
+	return 0
+}
+
+// getDomainName is a helper for getting the domain name in dns wire format
+// at offset off in data; it returns the name and the offset after the name
+func getDomainName(data []byte, off int) (string, int) {
+	var labels []string
+	next := -1
+	for hops := 0; off < len(data) && hops < len(data); hops++ {
+		l := int(data[off])
+		if l == 0 {
+			// end of name
+			off++
+			break
+		}
+		if l&0xc0 == 0xc0 {
+			// compression pointer
+			if off+1 >= len(data) {
+				off = len(data)
+				break
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			off = (l&0x3f)<<8 | int(data[off+1])
+			continue
+		}
+		if off+1+l > len(data) {
+			off = len(data)
+			break
+		}
+		labels = append(labels, string(data[off+1:off+1+l]))
+		off += 1 + l
+	}
+	if next >= 0 {
+		off = next
+	}
+	return strings.Join(labels, "."), off
+}
+
+// getDomainNames is a helper for getting the list of domain names in dns
+// wire format in data, e.g., in dhcp options
+func getDomainNames(data []byte) []string {
+	var names []string
+	for off := 0; off < len(data); {
+		var name string
+		name, off = getDomainName(data, off)
+		names = append(names, name)
+	}
+	return names
+}
+
+// setAddrTimestamp sets the timestamp of address info addr to the packet's
+func setAddrTimestamp(addr *dev.AddrInfo, packet gopacket.Packet) {
+	if addr != nil {
+		addr.SetTimestamp(packet.Metadata().Timestamp)
+	}
+}
+
+// countParseError counts a packet with a decoding error in layer
+func countParseError(layer string) {
+	parseErrors[layer]++
+}
+
+// ParseErrors returns the number of packets with decoding errors by layer;
+// the caller must lock the device table
+func ParseErrors() map[string]int {
+	errors := make(map[string]int, len(parseErrors))
+	for layer, count := range parseErrors {
+		errors[layer] = count
+	}
+	return errors
+}
+
+// parseDecodeErrors counts decoding errors of the packet by the last layer
+// decoded before the error, i.e., the layer that is invalid or contains the
+// invalid payload
+func parseDecodeErrors(packet gopacket.Packet) {
+	if packet.ErrorLayer() == nil {
+		return
+	}
+	layer := "Unknown"
+	pktLayers := packet.Layers()
+	if n := len(pktLayers); n > 1 {
+		layer = pktLayers[n-2].LayerType().String()
+	}
+	debug("Decoding error in " + layer)
+	countParseError(layer)
+}
+
 // updateStatistics updates statistics
 func updateStatistics(packet gopacket.Packet) {
 	// get addresses
@@ -117,10 +272,32 @@ func parsePeers(packet gopacket.Packet) {
 	dev.IPPeers.Add(netDst)
 }
 
+// parseSource records that the source MAC address was seen on the network
+// interface or pcap file source
+func parseSource(packet gopacket.Packet, source string) {
+	if source == "" {
+		return
+	}
+	linkSrc, _ := getMacs(packet)
+	if device := devices.Get(linkSrc); device != nil {
+		iface := device.Interfaces.Add(source)
+		iface.SetTimestamp(packet.Metadata().Timestamp)
+		iface.Packets++
+	}
+}
+
 // Parse parses the packet
 func Parse(packet gopacket.Packet) {
-	// lock devices
+	ParseSource(packet, "")
+}
+
+// ParseSource parses the packet captured on the network interface or read
+// from the pcap file source
+func ParseSource(packet gopacket.Packet, source string) {
+	// lock devices and set vlan and timestamp of the packet
 	devices.Lock()
+	devices.SetVLAN(getVlan(packet))
+	devices.SetPacketTime(packet.Metadata().Timestamp)
 
 	// parse packet
 	parseSrcMac(packet)
@@ -133,8 +310,13 @@ func Parse(packet gopacket.Packet) {
 	parseIgmp(packet)
 	parseMld(packet)
 	parseDhcp(packet)
+	parseMdns(packet)
 	parseStp(packet)
+	parseCdp(packet)
+	parseLldp(packet)
 	parsePlc(packet)
+	parseSource(packet, source)
+	parseDecodeErrors(packet)
 	updateStatistics(packet)
 
 	// unlock devices
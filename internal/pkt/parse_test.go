@@ -88,7 +88,7 @@ func TestSetPeers(t *testing.T) {
 		"(pkts: 1)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 1)\n" +
 		"  MAC Peers:\n" +
 		"    MAC: 06:05:04:03:02:01                       " +
@@ -113,7 +113,7 @@ func TestSetPeers(t *testing.T) {
 		"(pkts: 1)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 1)\n\n"
 	got = buf.String()
 	if got != want {
@@ -142,10 +142,118 @@ func TestParse(t *testing.T) {
 		"(pkts: 1)\n" +
 		"=================================================" +
 		"=====================\n" +
-		"MAC: 01:02:03:04:05:06                           " +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
 		"(age: -1, pkts: 1)\n\n"
 	got = buf.String()
 	if got != want {
 		t.Errorf("got = %s; want %s", got, want)
 	}
 }
+
+func TestParseSource(t *testing.T) {
+	var buf bytes.Buffer
+	var want, got string
+
+	devices = &dev.DeviceMap{}
+	ParseSource(testParseCreatePacket(), "eth0")
+	ParseSource(testParseCreatePacket(), "eth1")
+	ParseSource(testParseCreatePacket(), "eth1")
+	devices.Print(&buf)
+	want = "=================================================" +
+		"=====================\n" +
+		"Devices: 1                                       " +
+		"(pkts: 3)\n" +
+		"=================================================" +
+		"=====================\n" +
+		"MAC: 01:02:03:04:05:06 [multicast]               " +
+		"(age: -1, pkts: 3)\n" +
+		"  Interfaces:\n" +
+		"    Interface: eth0                              " +
+		"(age: -1, pkts: 1)\n" +
+		"    Interface: eth1                              " +
+		"(age: -1, pkts: 2)\n\n"
+	got = buf.String()
+	if got != want {
+		t.Errorf("got = %s; want %s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	devices = &dev.DeviceMap{}
+	parseErrors = make(map[string]int)
+
+	// valid packet
+	Parse(testParseCreatePacket())
+	if got := ParseErrors(); len(got) != 0 {
+		t.Errorf("got = %v; want no errors", got)
+	}
+
+	// packets with truncated ipv4 header
+	data := testParseCreatePacket().Data()[:20]
+	for i := 0; i < 2; i++ {
+		Parse(gopacket.NewPacket(data, layers.LayerTypeEthernet,
+			gopacket.Default))
+	}
+	got := ParseErrors()
+	if len(got) != 1 || got["IPv4"] != 2 {
+		t.Errorf("got = %v; want 2 IPv4 errors", got)
+	}
+}
+
+func TestGetDomainNames(t *testing.T) {
+	var want, got []string
+
+	// test empty
+	got = getDomainNames(nil)
+	if len(got) != 0 {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+
+	// test two names
+	data := []byte("\x07example\x03com\x00\x04test\x00")
+	want = []string{"example.com", "test"}
+	got = getDomainNames(data)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+}
+
+func TestGetDomainNamesCompressed(t *testing.T) {
+	var want, got []string
+
+	// test two names, second one with compression pointer
+	data := []byte("\x07example\x03com\x00\x04test\xc0\x00")
+	want = []string{"example.com", "test.example.com"}
+	got = getDomainNames(data)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+}
+
+func TestGetVlan(t *testing.T) {
+	// test untagged packet
+	if got := getVlan(testParseCreatePacket()); got != 0 {
+		t.Errorf("got = %d; want 0", got)
+	}
+
+	// test tagged packet
+	pktBuf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(pktBuf, gopacket.SerializeOptions{},
+		&layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+			DstMAC:       net.HardwareAddr{6, 5, 4, 3, 2, 1},
+			EthernetType: layers.EthernetTypeDot1Q,
+		},
+		&layers.Dot1Q{
+			VLANIdentifier: 10,
+			Type:           layers.EthernetTypeIPv4,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt := gopacket.NewPacket(pktBuf.Bytes(), layers.LayerTypeEthernet,
+		gopacket.Default)
+	if got := getVlan(pkt); got != 10 {
+		t.Errorf("got = %d; want 10", got)
+	}
+}
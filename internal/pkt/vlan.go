@@ -13,8 +13,7 @@ func parseVlan(packet gopacket.Packet) {
 		vlan, _ := vlanLayer.(*layers.Dot1Q)
 		linkSrc, _ := getMacs(packet)
 		dev := devices.Add(linkSrc)
-		v := dev.VLANs.Add(uint32(vlan.VLANIdentifier))
-		v.Type = "VLAN"
+		v := dev.VLANs.Add(uint32(vlan.VLANIdentifier), "VLAN")
 		v.SetTimestamp(packet.Metadata().Timestamp)
 		v.Packets++
 	}
@@ -14,8 +14,7 @@ func parseVxlan(packet gopacket.Packet) {
 		if vxlan.ValidIDFlag {
 			linkSrc, _ := getMacs(packet)
 			dev := devices.Add(linkSrc)
-			v := dev.VXLANs.Add(vxlan.VNI)
-			v.Type = "VXLAN"
+			v := dev.VXLANs.Add(vxlan.VNI, "VXLAN")
 			v.SetTimestamp(packet.Metadata().Timestamp)
 			v.Packets++
 		}